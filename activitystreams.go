@@ -0,0 +1,53 @@
+package hashtag
+
+// ActivityStreamsHashtagType is the ActivityStreams type of hashtags.
+const ActivityStreamsHashtagType = "Hashtag"
+
+// ActivityStreamsHashtag is an ActivityStreams Hashtag object,
+// as used in the "tag" property of ActivityPub objects.
+//
+//	{"type": "Hashtag", "href": "https://example.com/tags/foo", "name": "#foo"}
+type ActivityStreamsHashtag struct {
+	// Type is always ActivityStreamsHashtagType.
+	Type string `json:"type"`
+
+	// Href is the destination of the hashtag.
+	//
+	// This is empty if the hashtag was not linked.
+	// ActivityPub expects absolute URLs here,
+	// so the Resolver should return absolute destinations.
+	Href string `json:"href,omitempty"`
+
+	// Name is the canonical form of the hashtag with a leading "#".
+	Name string `json:"name"`
+}
+
+// ActivityStreamsTags returns ActivityStreams Hashtag objects
+// for the given tags.
+// Use these for the "tag" property of an ActivityPub object.
+//
+//	pc := parser.NewContext()
+//	if err := md.Convert(src, &buf, parser.WithContext(pc)); err != nil {
+//		// ...
+//	}
+//	note.Tag = hashtag.ActivityStreamsTags(hashtag.Tags(pc))
+//
+// To include tags listed in the document's frontmatter,
+// merge them with MergeTags first.
+// Frontmatter tags that don't also appear inline are never linked,
+// so their objects have no "href".
+func ActivityStreamsTags(tags []Tag) []ActivityStreamsHashtag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	objs := make([]ActivityStreamsHashtag, len(tags))
+	for i, t := range tags {
+		objs[i] = ActivityStreamsHashtag{
+			Type: ActivityStreamsHashtagType,
+			Href: string(t.Destination),
+			Name: string(_hash) + t.Name,
+		}
+	}
+	return objs
+}
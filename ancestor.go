@@ -0,0 +1,132 @@
+package hashtag
+
+import (
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Ancestor specifies kinds of nodes that may contain hashtags.
+//
+// Ancestors may be combined with "|".
+//
+//	hashtag.LinkAncestor | hashtag.HeadingAncestor | hashtag.TableCellAncestor
+type Ancestor uint
+
+const (
+	// HeadingAncestor matches ATX and Setext headings.
+	HeadingAncestor Ancestor = 1 << iota
+
+	// TableCellAncestor matches cells of tables
+	// from the goldmark Table extension.
+	TableCellAncestor
+
+	// BlockquoteAncestor matches block quotes.
+	BlockquoteAncestor
+
+	// EmphasisAncestor matches emphasized and strongly emphasized text.
+	EmphasisAncestor
+
+	// LinkAncestor matches the text of links.
+	//
+	// Hashtags inside links would render as links nested inside links,
+	// which is invalid HTML.
+	LinkAncestor
+
+	// NoAncestor matches no nodes.
+	//
+	// Use it to recognize hashtags everywhere, including inside links,
+	// since a zero value means DefaultSkip.
+	NoAncestor
+)
+
+// DefaultSkip is the kinds of ancestors
+// inside which hashtags are not recognized by default.
+const DefaultSkip = LinkAncestor
+
+// skips reports whether hashtags are not recognized inside node
+// or any of its ancestors.
+func (a Ancestor) skips(node ast.Node) bool {
+	for n := node; n != nil; n = n.Parent() {
+		var kind Ancestor
+		switch n.Kind() {
+		case ast.KindLink:
+			kind = LinkAncestor
+		case ast.KindHeading:
+			kind = HeadingAncestor
+		case east.KindTableCell:
+			kind = TableCellAncestor
+		case ast.KindBlockquote:
+			kind = BlockquoteAncestor
+		case ast.KindEmphasis:
+			kind = EmphasisAncestor
+		default:
+			continue
+		}
+
+		if a&kind != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var _ parser.ASTTransformer = (*Parser)(nil)
+
+// Transform removes hashtags from inline ancestors
+// that the Parser skips: links and emphasis.
+//
+// These nodes are built only after the text inside them is parsed,
+// so Parse cannot see them.
+// Hashtags inside them are replaced with their text.
+//
+// The Parser must be registered as an AST transformer
+// in addition to an inline parser for this to take effect.
+// Extender does this automatically.
+// Otherwise, these hashtags remain in the AST,
+// and Renderer and ANSIRenderer render them as plain text.
+func (p *Parser) Transform(doc *ast.Document, _ text.Reader, _ parser.Context) {
+	skip := p.skip()
+	if skip&(LinkAncestor|EmphasisAncestor) == 0 {
+		return
+	}
+
+	var skipped []*Node
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if tag, ok := n.(*Node); ok && entering {
+			if skip.skips(tag.Parent()) {
+				skipped = append(skipped, tag)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, n := range skipped {
+		parent := n.Parent()
+		for c := n.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			parent.InsertBefore(parent, n, c)
+			c = next
+		}
+		parent.RemoveChild(parent, n)
+	}
+}
+
+// skip returns the kinds of ancestors skipped by the Parser.
+func (p *Parser) skip() Ancestor {
+	if p.Skip == 0 {
+		return DefaultSkip
+	}
+	return p.Skip
+}
+
+// skipped reports whether the node is inside an ancestor
+// that the Parser which parsed it skips.
+//
+// This is true only if the Parser was not registered
+// as an AST transformer to remove such nodes.
+func (n *Node) skipped() bool {
+	return n.skip != 0 && n.skip.skips(n.Parent())
+}
@@ -0,0 +1,166 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestParser_Skip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		extender Extender
+		give     string
+		want     string
+	}{
+		{
+			desc: "link/default",
+			give: "[see #foo](/x) #bar",
+			want: `<p><a href="/x">see #foo</a> <span class="hashtag">#bar</span></p>` + "\n",
+		},
+		{
+			desc: "link/nested emphasis",
+			give: "[see *#foo*](/x)",
+			want: `<p><a href="/x">see <em>#foo</em></a></p>` + "\n",
+		},
+		{
+			desc:     "link/allowed",
+			extender: Extender{Skip: NoAncestor},
+			give:     "[see #foo](/x)",
+			want:     `<p><a href="/x">see <span class="hashtag">#foo</span></a></p>` + "\n",
+		},
+		{
+			desc:     "link/not skipped",
+			extender: Extender{Skip: HeadingAncestor},
+			give:     "[see #foo](/x)",
+			want:     `<p><a href="/x">see <span class="hashtag">#foo</span></a></p>` + "\n",
+		},
+		{
+			desc: "not a link",
+			give: "[see #foo]",
+			want: `<p>[see <span class="hashtag">#foo</span>]</p>` + "\n",
+		},
+		{
+			desc: "heading/default",
+			give: "# Title #foo",
+			want: `<h1>Title <span class="hashtag">#foo</span></h1>` + "\n",
+		},
+		{
+			desc:     "heading",
+			extender: Extender{Skip: HeadingAncestor},
+			give:     "# Title #foo\n\nTitle #bar\n===\n\nBody #baz",
+			want: "<h1>Title #foo</h1>\n" +
+				"<h1>Title #bar</h1>\n" +
+				`<p>Body <span class="hashtag">#baz</span></p>` + "\n",
+		},
+		{
+			desc:     "table cell",
+			extender: Extender{Skip: TableCellAncestor},
+			give:     "| #foo |\n| --- |\n| #bar |\n\n#baz",
+			want: "<table>\n<thead>\n<tr>\n<th>#foo</th>\n</tr>\n</thead>\n" +
+				"<tbody>\n<tr>\n<td>#bar</td>\n</tr>\n</tbody>\n</table>\n" +
+				`<p><span class="hashtag">#baz</span></p>` + "\n",
+		},
+		{
+			desc:     "blockquote",
+			extender: Extender{Skip: BlockquoteAncestor},
+			give:     "> - #foo\n\n#bar",
+			want: "<blockquote>\n<ul>\n<li>#foo</li>\n</ul>\n</blockquote>\n" +
+				`<p><span class="hashtag">#bar</span></p>` + "\n",
+		},
+		{
+			desc:     "emphasis",
+			extender: Extender{Skip: EmphasisAncestor},
+			give:     "*#foo* **#bar** #baz",
+			want:     `<p><em>#foo</em> <strong>#bar</strong> <span class="hashtag">#baz</span></p>` + "\n",
+		},
+		{
+			desc:     "combined",
+			extender: Extender{Skip: DefaultSkip | HeadingAncestor | EmphasisAncestor},
+			give:     "# *#foo* #bar\n\n*#baz* [#qux](/x) #quux",
+			want: "<h1><em>#foo</em> #bar</h1>\n" +
+				`<p><em>#baz</em> <a href="/x">#qux</a> <span class="hashtag">#quux</span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(goldmark.WithExtensions(
+				extension.Table,
+				&tt.extender,
+			))
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestParser_SkipWithoutTransform(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		skip Ancestor
+		give string
+		want string
+	}{
+		{
+			desc: "link/default",
+			give: "[see #foo](/x) #bar",
+			want: `<p><a href="/x">see #foo</a> <span class="hashtag"><a href="/tags/bar">#bar</a></span></p>` + "\n",
+		},
+		{
+			desc: "link/allowed",
+			skip: NoAncestor,
+			give: "[see #foo](/x)",
+			want: `<p><a href="/x">see <span class="hashtag"><a href="/tags/foo">#foo</a></span></a></p>` + "\n",
+		},
+		{
+			desc: "emphasis",
+			skip: EmphasisAncestor,
+			give: "*#foo* #bar",
+			want: `<p><em>#foo</em> <span class="hashtag"><a href="/tags/bar">#bar</a></span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Register the Parser only as an inline parser.
+			md := goldmark.New(
+				goldmark.WithParserOptions(
+					parser.WithInlineParsers(
+						util.Prioritized(&Parser{Skip: tt.skip}, 999),
+					),
+				),
+				goldmark.WithRendererOptions(
+					renderer.WithNodeRenderers(
+						util.Prioritized(&Renderer{
+							Resolver: prefixResolver{},
+						}, 999),
+					),
+				),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package hashtag
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// DefaultSGR is the ANSI Select Graphic Rendition used by ANSIRenderer
+// if ANSIRenderer.SGR is not set: bold cyan.
+const DefaultSGR = "1;36"
+
+// ANSIRenderer renders hashtag nodes for terminals
+// with ANSI escape sequences.
+//
+//	#foo
+//
+// Renders as the following by default,
+// where ESC is the escape character (0x1b).
+//
+//	ESC[1;36m#fooESC[0m
+//
+// If the Resolver returns a destination,
+// the hashtag is wrapped in an OSC 8 hyperlink to it.
+//
+//	ESC[1;36mESC]8;;/tags/fooESC\#fooESC]8;;ESC\ESC[0m
+//
+// Pair it with a renderer that writes the rest of the document
+// for the terminal, e.g. PlainTextRenderer.
+//
+//	renderer.NewRenderer(
+//		renderer.WithNodeRenderers(
+//			util.Prioritized(&hashtag.PlainTextRenderer{}, 1000),
+//			util.Prioritized(&hashtag.ANSIRenderer{
+//				NoColor: os.Getenv("NO_COLOR") != "",
+//			}, 999),
+//		),
+//	)
+type ANSIRenderer struct {
+	// Resolver is used to resolve the destinations of hashtags.
+	// Hashtags with a destination are rendered as OSC 8 hyperlinks.
+	//
+	// If this is unset, hashtags are not linked.
+	Resolver Resolver
+
+	// OnResolveError specifies how to handle errors
+	// returned by the Resolver.
+	//
+	// ErrorClassOnResolveError behaves like PlainTextOnResolveError
+	// because terminals have no classes.
+	// Errors are recorded for ResolveErrors regardless of the policy.
+	//
+	// Defaults to AbortOnResolveError.
+	OnResolveError ResolveErrorPolicy
+
+	// AllowedSchemes are the URL schemes allowed in destinations
+	// reported by the Resolver.
+	// Relative URLs are always allowed.
+	//
+	// Hashtags with destinations that use other schemes,
+	// or that goldmark considers dangerous (see html.IsDangerousURL),
+	// are not linked.
+	//
+	// Defaults to "http" and "https".
+	AllowedSchemes []string
+
+	// SGR is the Select Graphic Rendition parameters
+	// used to style hashtags, without the leading "ESC[" or trailing "m".
+	// For example, "1" for bold or "4;35" for underlined magenta.
+	//
+	// Defaults to DefaultSGR.
+	SGR string
+
+	// NoColor disables styling.
+	// Hashtags are written as plain text.
+	NoColor bool
+
+	// NoHyperlinks disables OSC 8 hyperlinks
+	// for terminals that don't support them.
+	NoHyperlinks bool
+}
+
+var _ renderer.NodeRenderer = (*ANSIRenderer)(nil)
+
+// RegisterFuncs registers rendering functions from this renderer onto the
+// provided registerer.
+func (r *ANSIRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(Kind, r.Render)
+}
+
+// Render renders a hashtag node with ANSI escape sequences.
+func (r *ANSIRenderer) Render(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n, ok := node.(*Node)
+	if !ok {
+		return ast.WalkStop, fmt.Errorf("unexpected node %T, expected *Node", node)
+	}
+
+	// Hashtags that the Parser skips render as their text.
+	if !entering || n.skipped() {
+		return ast.WalkContinue, nil
+	}
+
+	var dest []byte
+	if r.Resolver != nil && !r.NoHyperlinks {
+		res, err := resolve(r.Resolver, n)
+		if err != nil {
+			rerr := reportResolveError(src, n, err)
+			if r.OnResolveError == AbortOnResolveError {
+				return ast.WalkStop, rerr
+			}
+			res.Destination = nil
+		}
+		if len(res.Destination) > 0 && isAllowedDestination(res.Destination, r.AllowedSchemes) {
+			dest = terminalURL(res.Destination)
+		}
+	}
+
+	if !r.NoColor {
+		sgr := r.SGR
+		if len(sgr) == 0 {
+			sgr = DefaultSGR
+		}
+		_, _ = w.WriteString("\x1b[" + sgr + "m")
+	}
+	if len(dest) > 0 {
+		_, _ = w.WriteString("\x1b]8;;")
+		_, _ = w.Write(dest)
+		_, _ = w.WriteString("\x1b\\")
+	}
+
+	_ = w.WriteByte(_hash)
+	_, _ = w.Write(n.Tag)
+
+	if len(dest) > 0 {
+		_, _ = w.WriteString("\x1b]8;;\x1b\\")
+	}
+	if !r.NoColor {
+		_, _ = w.WriteString("\x1b[0m")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// terminalURL percent-encodes the destination for use in an OSC 8 hyperlink.
+//
+// util.URLEscape leaves invalid UTF-8 bytes alone,
+// and some terminals treat 0x9c as the end of the escape sequence,
+// so all bytes outside printable ASCII are percent-encoded
+// to prevent a destination from injecting its own escape sequences.
+func terminalURL(dest []byte) []byte {
+	const hex = "0123456789ABCDEF"
+
+	dest = util.URLEscape(dest, false)
+	out := make([]byte, 0, len(dest))
+	for _, c := range dest {
+		if c < 0x20 || c >= 0x7f {
+			out = append(out, '%', hex[c>>4], hex[c&0xf])
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
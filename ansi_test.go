@@ -0,0 +1,204 @@
+package hashtag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestANSIRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		renderer ANSIRenderer
+		want     string
+	}{
+		{
+			desc: "default",
+			want: "Foo \x1b[1;36m#bar\x1b[0m.\n",
+		},
+		{
+			desc:     "style",
+			renderer: ANSIRenderer{SGR: "1"},
+			want:     "Foo \x1b[1m#bar\x1b[0m.\n",
+		},
+		{
+			desc:     "no color",
+			renderer: ANSIRenderer{NoColor: true},
+			want:     "Foo #bar.\n",
+		},
+		{
+			desc:     "hyperlink",
+			renderer: ANSIRenderer{Resolver: constResolver{Dest: "/tags/bar"}},
+			want:     "Foo \x1b[1;36m\x1b]8;;/tags/bar\x1b\\#bar\x1b]8;;\x1b\\\x1b[0m.\n",
+		},
+		{
+			desc: "hyperlink/no color",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{Dest: "/tags/bar"},
+				NoColor:  true,
+			},
+			want: "Foo \x1b]8;;/tags/bar\x1b\\#bar\x1b]8;;\x1b\\.\n",
+		},
+		{
+			desc: "no hyperlinks",
+			renderer: ANSIRenderer{
+				Resolver:     constResolver{Dest: "/tags/bar"},
+				NoHyperlinks: true,
+			},
+			want: "Foo \x1b[1;36m#bar\x1b[0m.\n",
+		},
+		{
+			desc: "no destination",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{},
+				NoColor:  true,
+			},
+			want: "Foo #bar.\n",
+		},
+		{
+			desc: "control characters",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{Dest: "/a\x1b\\b\x07\x9c"},
+				NoColor:  true,
+			},
+			want: "Foo \x1b]8;;/a%1B%5Cb%07%9C\x1b\\#bar\x1b]8;;\x1b\\.\n",
+		},
+		{
+			desc: "dangerous destination",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{Dest: "javascript:alert(1)"},
+				NoColor:  true,
+			},
+			want: "Foo #bar.\n",
+		},
+		{
+			desc: "dangerous destination/allowed scheme",
+			renderer: ANSIRenderer{
+				Resolver:       constResolver{Dest: "file:///etc/passwd"},
+				AllowedSchemes: []string{"file"},
+				NoColor:        true,
+			},
+			want: "Foo #bar.\n",
+		},
+		{
+			desc: "disallowed scheme",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{Dest: "obsidian://search?query=bar"},
+				NoColor:  true,
+			},
+			want: "Foo #bar.\n",
+		},
+		{
+			desc: "allowed scheme",
+			renderer: ANSIRenderer{
+				Resolver:       constResolver{Dest: "obsidian://search?query=bar"},
+				AllowedSchemes: []string{"obsidian"},
+				NoColor:        true,
+			},
+			want: "Foo \x1b]8;;obsidian://search?query=bar\x1b\\#bar\x1b]8;;\x1b\\.\n",
+		},
+		{
+			desc: "absolute destination",
+			renderer: ANSIRenderer{
+				Resolver: constResolver{Dest: "https://example.com/tags/bar"},
+				NoColor:  true,
+			},
+			want: "Foo \x1b]8;;https://example.com/tags/bar\x1b\\#bar\x1b]8;;\x1b\\.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{}),
+				goldmark.WithRenderer(renderer.NewRenderer(
+					renderer.WithNodeRenderers(
+						util.Prioritized(&PlainTextRenderer{}, 1000),
+						util.Prioritized(&tt.renderer, 999),
+					),
+				)),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte("Foo #bar."), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestANSIRenderer_ResolveError(t *testing.T) {
+	t.Parallel()
+
+	giveErr := errors.New("great sadness")
+	md := goldmark.New(
+		goldmark.WithExtensions(&Extender{}),
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(
+				util.Prioritized(&PlainTextRenderer{}, 1000),
+				util.Prioritized(&ANSIRenderer{
+					Resolver: constResolver{Err: giveErr},
+				}, 999),
+			),
+		)),
+	)
+
+	var buff bytes.Buffer
+	err := md.Convert([]byte("Foo #bar."), &buff)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, giveErr)
+
+	var rerr *ResolveError
+	require.ErrorAs(t, err, &rerr)
+	assert.Equal(t, "bar", string(rerr.Tag))
+	assert.Equal(t, Position{Offset: 4, Line: 1, Column: 5}, rerr.Position)
+}
+
+func TestANSIRenderer_OnResolveError(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []ResolveErrorPolicy{
+		PlainTextOnResolveError,
+		ErrorClassOnResolveError,
+	} {
+		policy := policy
+		t.Run(fmt.Sprint(policy), func(t *testing.T) {
+			t.Parallel()
+
+			giveErr := errors.New("great sadness")
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{}),
+				goldmark.WithRenderer(renderer.NewRenderer(
+					renderer.WithNodeRenderers(
+						util.Prioritized(&PlainTextRenderer{}, 1000),
+						util.Prioritized(&ANSIRenderer{
+							Resolver:       constResolver{Dest: "/tags/bar", Err: giveErr},
+							OnResolveError: policy,
+						}, 999),
+					),
+				)),
+			)
+
+			pc := parser.NewContext()
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte("Foo #bar."), &buff, parser.WithContext(pc)))
+			assert.Equal(t, "Foo \x1b[1;36m#bar\x1b[0m.\n", buff.String())
+
+			errs := ResolveErrors(pc)
+			require.Len(t, errs, 1)
+			assert.ErrorIs(t, errs[0], giveErr)
+		})
+	}
+}
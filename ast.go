@@ -1,6 +1,11 @@
 package hashtag
 
-import "github.com/yuin/goldmark/ast"
+import (
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
 
 // Kind is the kind of hashtag AST nodes.
 var Kind = ast.NewNodeKind("Hashtag")
@@ -11,6 +16,18 @@ type Node struct {
 
 	// Tag is the portion of the hashtag following the '#'.
 	Tag []byte
+
+	// context is the parser.Context of the document
+	// this node was parsed from, if any.
+	context parser.Context
+
+	// report collects information about the node while rendering.
+	// It is shared by all nodes parsed from the same document.
+	report *report
+
+	// skip is the kinds of ancestors skipped
+	// by the Parser that parsed this node, if any.
+	skip Ancestor
 }
 
 // Kind reports the kind of hashtag nodes.
@@ -22,3 +39,13 @@ func (n *Node) Dump(src []byte, level int) {
 		"Tag": string(n.Tag),
 	}, nil)
 }
+
+// Canonical returns the canonical form of a hashtag:
+// the tag without a leading "#", in lower case.
+//
+// Hashtags with the same canonical form are considered the same tag.
+//
+//	Canonical("#Go") == Canonical("go") // true
+func Canonical(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
@@ -38,3 +38,28 @@ func TestNodeDump(t *testing.T) {
 		"",
 	}, "\n"), string(got))
 }
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give string
+		want string
+	}{
+		{give: "", want: ""},
+		{give: "foo", want: "foo"},
+		{give: "#foo", want: "foo"},
+		{give: "Foo/Bar", want: "foo/bar"},
+		{give: "#ÉTÉ", want: "été"},
+		{give: "##foo", want: "#foo"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.give, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Canonical(tt.give))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package hashtag
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Attribute defines an attribute to be added to an HTML tag.
+//
+//	Attribute{ Attr: "class", Value: "tag"}
+//
+// Will result in <a class="tag" ...>
+//
+// Use ValueFunc for attributes whose value depends on the hashtag.
+//
+//	Attribute{
+//		Name: "data-tag",
+//		ValueFunc: func(n *hashtag.Node) string {
+//			return string(n.Tag)
+//		},
+//	}
+type Attribute struct {
+	Name  string
+	Value string
+
+	// ValueFunc, if set, computes the value of the attribute
+	// for each hashtag. Value is ignored if ValueFunc is set.
+	ValueFunc func(*Node) string
+}
+
+// AttributeError reports an invalid Attribute.
+type AttributeError struct {
+	// Name is the name of the invalid attribute.
+	Name string
+
+	// Reason describes why the attribute is invalid.
+	Reason string
+}
+
+func (e *AttributeError) Error() string {
+	return fmt.Sprintf("attribute %q: %v", e.Name, e.Reason)
+}
+
+// ValidateAttributes reports errors for attributes that cannot be rendered.
+//
+// Attributes are invalid if:
+//
+//   - their names are not valid HTML attribute names
+//   - they are named "href", which is reserved for the link destination
+//   - they have the same name as another attribute in the list
+//     (names are case-insensitive)
+//
+// The returned error joins an *AttributeError for each problem.
+// The Renderer validates attributes before rendering hashtags.
+func ValidateAttributes(attrs []Attribute) error {
+	var errs []error
+	for i, attr := range attrs {
+		switch {
+		case !isAttributeName(attr.Name):
+			errs = append(errs, &AttributeError{Name: attr.Name, Reason: "invalid name"})
+		case strings.EqualFold(attr.Name, "href"):
+			errs = append(errs, &AttributeError{Name: attr.Name, Reason: "reserved name"})
+		case hasAttribute(attrs[:i], attr.Name):
+			errs = append(errs, &AttributeError{Name: attr.Name, Reason: "duplicate name"})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func hasAttribute(attrs []Attribute, name string) bool {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAttributeName reports whether name is a valid HTML attribute name.
+//
+// Per the HTML specification, attribute names must be non-empty,
+// and must not contain controls, spaces, '"', "'", '>', '/', '=',
+// or noncharacters.
+func isAttributeName(name string) bool {
+	if name == "" || !utf8.ValidString(name) {
+		return false
+	}
+
+	for _, r := range name {
+		switch {
+		case unicode.IsControl(r), unicode.IsSpace(r), isNoncharacter(r):
+			return false
+		case r == '"', r == '\'', r == '>', r == '/', r == '=':
+			return false
+		}
+	}
+	return true
+}
+
+// isNoncharacter reports whether r is a Unicode noncharacter:
+// U+FDD0 to U+FDEF, and the last two code points of each plane.
+func isNoncharacter(r rune) bool {
+	return (0xFDD0 <= r && r <= 0xFDEF) || r&0xFFFE == 0xFFFE
+}
+
+// expandAttributes returns attrs with the values of all attributes
+// that have a ValueFunc computed for the given node.
+//
+// attrs is returned as-is if none of them have a ValueFunc.
+func expandAttributes(attrs []Attribute, n *Node) []Attribute {
+	var expanded []Attribute
+	for i, attr := range attrs {
+		if attr.ValueFunc == nil {
+			continue
+		}
+
+		if expanded == nil {
+			expanded = slices.Clone(attrs)
+		}
+		expanded[i] = Attribute{Name: attr.Name, Value: attr.ValueFunc(n)}
+	}
+	if expanded == nil {
+		return attrs
+	}
+	return expanded
+}
+
+// mergeAttributes returns a copy of attrs with extra merged into it.
+//
+// Attributes in extra replace attributes in attrs with the same name,
+// except "class" attributes, which are combined.
+func mergeAttributes(attrs, extra []Attribute) []Attribute {
+	switch {
+	case len(extra) == 0:
+		return attrs
+	case len(attrs) == 0:
+		return extra
+	}
+
+	merged := make([]Attribute, len(attrs), len(attrs)+len(extra))
+	copy(merged, attrs)
+	for _, attr := range extra {
+		idx := -1
+		for i, a := range merged {
+			if strings.EqualFold(a.Name, attr.Name) {
+				idx = i
+				break
+			}
+		}
+
+		switch {
+		case idx < 0:
+			merged = append(merged, attr)
+		case strings.EqualFold(attr.Name, "class"):
+			merged[idx].Value += " " + attr.Value
+		default:
+			merged[idx] = attr
+		}
+	}
+	return merged
+}
@@ -0,0 +1,226 @@
+package hashtag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    []Attribute
+		wantErr []string
+	}{
+		{desc: "empty"},
+		{
+			desc: "valid",
+			give: []Attribute{
+				{Name: "class", Value: "a"},
+				{Name: "data-foo", Value: `"><script>`},
+				{Name: "aria-label", Value: "b"},
+				{Name: "xml:lang", Value: "en"},
+				{Name: "@click", Value: "c"},
+			},
+		},
+		{
+			desc:    "empty name",
+			give:    []Attribute{{Name: ""}},
+			wantErr: []string{`attribute "": invalid name`},
+		},
+		{
+			desc: "break out",
+			give: []Attribute{
+				{Name: `foo"><script>alert(1)</script`},
+				{Name: "a b"},
+				{Name: "a=b"},
+				{Name: "a/b"},
+				{Name: "a'b"},
+				{Name: "a\x00b"},
+				{Name: "a\ufdd0b"},
+				{Name: "a\xffb"},
+			},
+			wantErr: []string{
+				`attribute "foo\"><script>alert(1)</script": invalid name`,
+				`attribute "a b": invalid name`,
+				`attribute "a=b": invalid name`,
+				`attribute "a/b": invalid name`,
+				`attribute "a'b": invalid name`,
+				`attribute "a\x00b": invalid name`,
+				`attribute "a\ufdd0b": invalid name`,
+				`attribute "a\xffb": invalid name`,
+			},
+		},
+		{
+			desc:    "href",
+			give:    []Attribute{{Name: "HREF", Value: "/foo"}},
+			wantErr: []string{`attribute "HREF": reserved name`},
+		},
+		{
+			desc: "duplicate",
+			give: []Attribute{
+				{Name: "title", Value: "a"},
+				{Name: "class", Value: "b"},
+				{Name: "Title", Value: "c"},
+			},
+			wantErr: []string{`attribute "Title": duplicate name`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateAttributes(tt.give)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+
+			var attrErr *AttributeError
+			assert.ErrorAs(t, err, &attrErr)
+		})
+	}
+}
+
+func TestRenderer_InvalidAttributes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("static", func(t *testing.T) {
+		t.Parallel()
+
+		md := goldmark.New(goldmark.WithExtensions(&Extender{
+			Resolver:   constResolver{Dest: "/foo"},
+			Attributes: []Attribute{{Name: `x"onclick="alert(1)`}},
+		}))
+
+		err := md.Convert([]byte("#foo"), new(bytes.Buffer))
+		assert.ErrorContains(t, err, "invalid Attributes")
+	})
+
+	t.Run("wrapper", func(t *testing.T) {
+		t.Parallel()
+
+		md := goldmark.New(goldmark.WithExtensions(&Extender{
+			WrapperAttributes: []Attribute{{Name: "id"}, {Name: "id"}},
+		}))
+
+		err := md.Convert([]byte("#foo"), new(bytes.Buffer))
+		assert.ErrorContains(t, err, "invalid WrapperAttributes")
+	})
+
+	t.Run("resolver", func(t *testing.T) {
+		t.Parallel()
+
+		md := goldmark.New(goldmark.WithExtensions(&Extender{
+			Resolver: detailedResolver{
+				Resolution: Resolution{
+					Destination: []byte("/foo"),
+					Attributes:  []Attribute{{Name: "href", Value: "javascript:alert(1)"}},
+				},
+			},
+			OnResolveError: PlainTextOnResolveError,
+		}))
+
+		pc := parser.NewContext()
+		var buff bytes.Buffer
+		require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+		assert.Equal(t, `<p><span class="hashtag">#foo</span></p>`+"\n", buff.String())
+
+		errs := ResolveErrors(pc)
+		require.Len(t, errs, 1)
+		var attrErr *AttributeError
+		require.True(t, errors.As(errs[0], &attrErr))
+		assert.Equal(t, "href", attrErr.Name)
+	})
+}
+
+func TestExpandAttributes(t *testing.T) {
+	t.Parallel()
+
+	node := &Node{Tag: []byte("foo")}
+
+	t.Run("static", func(t *testing.T) {
+		t.Parallel()
+
+		attrs := []Attribute{{Name: "a", Value: "b"}}
+		got := expandAttributes(attrs, node)
+		assert.Equal(t, attrs, got)
+		assert.Same(t, &attrs[0], &got[0], "must not copy")
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		t.Parallel()
+
+		tagValue := func(n *Node) string { return "tag-" + string(n.Tag) }
+		attrs := []Attribute{
+			{Name: "a", Value: "b"},
+			{Name: "c", Value: "ignored", ValueFunc: tagValue},
+		}
+		got := expandAttributes(attrs, node)
+		assert.Equal(t, []Attribute{
+			{Name: "a", Value: "b"},
+			{Name: "c", Value: "tag-foo"},
+		}, got)
+		assert.NotNil(t, attrs[1].ValueFunc, "must not modify input")
+	})
+}
+
+func TestMergeAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc  string
+		attrs []Attribute
+		extra []Attribute
+		want  []Attribute
+	}{
+		{desc: "empty"},
+		{
+			desc:  "no extra",
+			attrs: []Attribute{{Name: "foo", Value: "bar"}},
+			want:  []Attribute{{Name: "foo", Value: "bar"}},
+		},
+		{
+			desc:  "only extra",
+			extra: []Attribute{{Name: "foo", Value: "bar"}},
+			want:  []Attribute{{Name: "foo", Value: "bar"}},
+		},
+		{
+			desc:  "replace",
+			attrs: []Attribute{{Name: "foo", Value: "bar"}, {Name: "baz", Value: "qux"}},
+			extra: []Attribute{{Name: "FOO", Value: "quux"}},
+			want:  []Attribute{{Name: "FOO", Value: "quux"}, {Name: "baz", Value: "qux"}},
+		},
+		{
+			desc:  "combine classes",
+			attrs: []Attribute{{Name: "class", Value: "a"}},
+			extra: []Attribute{{Name: "class", Value: "b"}, {Name: "title", Value: "c"}},
+			want:  []Attribute{{Name: "class", Value: "a b"}, {Name: "title", Value: "c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			attrs := append([]Attribute(nil), tt.attrs...)
+			assert.Equal(t, tt.want, mergeAttributes(attrs, tt.extra))
+			assert.Equal(t, tt.attrs, attrs, "must not modify input")
+		})
+	}
+}
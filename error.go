@@ -0,0 +1,138 @@
+package hashtag
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// ResolveErrorPolicy specifies how the Renderer handles errors
+// returned by the Resolver.
+type ResolveErrorPolicy uint
+
+const (
+	// AbortOnResolveError stops rendering the document
+	// and reports the error from Render.
+	//
+	// This is the default policy.
+	AbortOnResolveError ResolveErrorPolicy = iota
+
+	// PlainTextOnResolveError renders hashtags that failed to resolve
+	// as if they had no destination.
+	PlainTextOnResolveError
+
+	// ErrorClassOnResolveError renders hashtags that failed to resolve
+	// as if they had no destination,
+	// and adds the error class to the hashtag's element.
+	ErrorClassOnResolveError
+)
+
+// DefaultErrorClass is the class added to hashtags that failed to resolve
+// with ErrorClassOnResolveError.
+const DefaultErrorClass = "hashtag-error"
+
+// ResolveError is an error encountered while resolving a hashtag.
+type ResolveError struct {
+	// Tag is the hashtag that failed to resolve.
+	Tag []byte
+
+	// Position is the position of the hashtag in the source document.
+	//
+	// This is the zero value if the position is unknown.
+	Position Position
+
+	// Err is the error returned by the Resolver.
+	Err error
+}
+
+func (e *ResolveError) Error() string {
+	if e.Position.Line == 0 {
+		return fmt.Sprintf("resolve hashtag %q: %v", e.Tag, e.Err)
+	}
+	return fmt.Sprintf("%v: resolve hashtag %q: %v", e.Position, e.Tag, e.Err)
+}
+
+// Unwrap returns the error returned by the Resolver.
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+// Position is a position inside a source document.
+type Position struct {
+	// Offset is the byte offset in the document, starting at 0.
+	Offset int `json:"offset"`
+
+	// Line and Column are the line number and byte column,
+	// both starting at 1.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String returns the position in the form "line:column".
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// positionOf returns the Position of the given offset in src.
+func positionOf(src []byte, offset int) Position {
+	if offset < 0 || offset > len(src) {
+		return Position{}
+	}
+
+	before := src[:offset]
+	return Position{
+		Offset: offset,
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: offset - bytes.LastIndexByte(before, '\n'),
+	}
+}
+
+// nodeOffset returns the offset of the hashtag in the source document,
+// or -1 if it's not known.
+func nodeOffset(n *Node) int {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			return t.Segment.Start
+		}
+	}
+	return -1
+}
+
+// ResolveErrors returns the errors encountered by the Renderer
+// while resolving hashtags in the document parsed with pc.
+//
+// Errors are collected regardless of the ResolveErrorPolicy,
+// so this may be used to log failures after a document is converted.
+// A hashtag's error is reported once
+// even if the document is rendered more than once.
+//
+//	pc := parser.NewContext()
+//	if err := md.Convert(src, &buf, parser.WithContext(pc)); err != nil {
+//		// ...
+//	}
+//	for _, err := range hashtag.ResolveErrors(pc) {
+//		log.Print(err)
+//	}
+func ResolveErrors(pc parser.Context) []*ResolveError {
+	r := getReport(pc)
+	if r == nil {
+		return nil
+	}
+	return r.resolveErrors()
+}
+
+// reportResolveError builds a ResolveError for the given hashtag
+// and records it in the document's report, if any.
+func reportResolveError(src []byte, n *Node, err error) *ResolveError {
+	rerr := &ResolveError{
+		Tag:      n.Tag,
+		Position: positionOf(src, nodeOffset(n)),
+		Err:      err,
+	}
+	if n.report != nil {
+		n.report.addError(n, rerr)
+	}
+	return rerr
+}
@@ -0,0 +1,96 @@
+package hashtag
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestPositionOf(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("foo\nbar baz\n\nqux")
+
+	tests := []struct {
+		desc   string
+		offset int
+		want   Position
+	}{
+		{desc: "start", offset: 0, want: Position{Offset: 0, Line: 1, Column: 1}},
+		{desc: "first line", offset: 2, want: Position{Offset: 2, Line: 1, Column: 3}},
+		{desc: "second line", offset: 8, want: Position{Offset: 8, Line: 2, Column: 5}},
+		{desc: "after blank line", offset: 13, want: Position{Offset: 13, Line: 4, Column: 1}},
+		{desc: "end", offset: len(src), want: Position{Offset: 16, Line: 4, Column: 4}},
+		{desc: "unknown", offset: -1, want: Position{}},
+		{desc: "out of bounds", offset: 100, want: Position{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, positionOf(src, tt.offset))
+		})
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	t.Parallel()
+
+	giveErr := errors.New("great sadness")
+
+	t.Run("with position", func(t *testing.T) {
+		t.Parallel()
+
+		err := &ResolveError{
+			Tag:      []byte("foo"),
+			Position: Position{Offset: 5, Line: 2, Column: 3},
+			Err:      giveErr,
+		}
+		assert.EqualError(t, err, `2:3: resolve hashtag "foo": great sadness`)
+		assert.ErrorIs(t, err, giveErr)
+	})
+
+	t.Run("without position", func(t *testing.T) {
+		t.Parallel()
+
+		err := &ResolveError{Tag: []byte("foo"), Err: giveErr}
+		assert.EqualError(t, err, `resolve hashtag "foo": great sadness`)
+	})
+}
+
+func TestResolveErrors_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, ResolveErrors(parser.NewContext()))
+}
+
+func TestResolveErrors_RenderTwice(t *testing.T) {
+	t.Parallel()
+
+	giveErr := errors.New("great sadness")
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		Resolver:       constResolver{Err: giveErr},
+		OnResolveError: PlainTextOnResolveError,
+	}))
+
+	src := []byte("#foo and #bar")
+	pc := parser.NewContext()
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, md.Renderer().Render(io.Discard, src, doc))
+	}
+
+	errs := ResolveErrors(pc)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "foo", string(errs[0].Tag))
+	assert.Equal(t, "bar", string(errs[1].Tag))
+}
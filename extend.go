@@ -1,6 +1,8 @@
 package hashtag
 
 import (
+	"html/template"
+
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
@@ -25,6 +27,9 @@ import (
 type Extender struct {
 	// Resolver specifies destination links for hashtags, if any.
 	//
+	// Implement ContextResolver to resolve links
+	// relative to the document being rendered.
+	//
 	// Defaults to no links.
 	Resolver Resolver
 
@@ -34,11 +39,114 @@ type Extender struct {
 	// variants for more information.
 	Variant Variant
 
+	// Frontmatter, if set, returns the metadata of the document
+	// so that documents may choose their hashtag syntax.
+	// See Parser.Frontmatter for details.
+	Frontmatter func(parser.Context) map[string]any
+
+	// Skip lists the kinds of ancestors
+	// inside which hashtags are not recognized.
+	//
+	// Defaults to DefaultSkip, which skips links.
+	// Set to NoAncestor to recognize hashtags everywhere.
+	// See Parser.Skip for details.
+	Skip Ancestor
+
 	// Attributes are added to the <a> tag.
 	//
 	// Attributes will only be applied if the tag can be resolved by the Resolver.
+	// Per-tag attributes reported by a DetailedResolver are merged with these.
 	// Defaults to no attributes.
 	Attributes []Attribute
+
+	// WrapperAttributes are added to the element that wraps hashtags.
+	//
+	// These are applied to all hashtags, whether they resolve or not.
+	// Defaults to no attributes.
+	WrapperAttributes []Attribute
+
+	// OnResolveError specifies how to handle errors from the Resolver.
+	//
+	// Errors are recorded in the document's parser.Context
+	// regardless of this setting. Use ResolveErrors to retrieve them.
+	//
+	// Defaults to AbortOnResolveError.
+	OnResolveError ResolveErrorPolicy
+
+	// ErrorClass is the class added to hashtags that failed to resolve
+	// when OnResolveError is ErrorClassOnResolveError.
+	//
+	// Defaults to DefaultErrorClass.
+	ErrorClass string
+
+	// MissingClass is the class added to hashtags
+	// that a DetailedResolver reported as missing.
+	//
+	// Defaults to DefaultMissingClass.
+	MissingClass string
+
+	// Element is the name of the HTML element that wraps hashtags.
+	//
+	// Defaults to DefaultElement.
+	Element string
+
+	// Class is the class of the element that wraps hashtags.
+	//
+	// Defaults to DefaultClass.
+	Class string
+
+	// LinkClass is the class of the <a> tag for hashtags with a destination.
+	//
+	// Defaults to no class.
+	LinkClass string
+
+	// NoWrapper specifies that hashtags should not be wrapped in an element.
+	//
+	// If set, hashtags with a destination are rendered as just an <a> tag,
+	// and hashtags without one are rendered as plain text.
+	// Classes and attributes meant for the wrapper are moved to the <a> tag,
+	// or kept on a bare wrapper for hashtags without a destination.
+	// See Renderer.NoWrapper for details.
+	NoWrapper bool
+
+	// AllowedSchemes are the URL schemes allowed in destinations
+	// reported by the Resolver.
+	// Relative URLs are always allowed.
+	//
+	// Hashtags with destinations that use other schemes are rendered
+	// as plain text unless goldmark's Unsafe option is set.
+	//
+	// Defaults to "http" and "https".
+	AllowedSchemes []string
+
+	// Display, if set, reports the text displayed for a hashtag
+	// in place of the hashtag as written in the document.
+	// See Renderer.Display for details.
+	//
+	// Defaults to the hashtag as written in the document.
+	Display func(*Node) string
+
+	// Markup specifies the semantic markup conventions to follow
+	// for hashtags (e.g. microformats2 or schema.org microdata).
+	//
+	// Defaults to no semantic markup.
+	Markup Markup
+
+	// Style, if set, assigns colours and icons to hashtags.
+	// See Renderer.Style for details.
+	Style *Style
+
+	// Template, if set, renders hashtags instead of the default HTML.
+	//
+	// The template is executed with a *TemplateData for each hashtag.
+	// See Renderer.Template for details.
+	Template *template.Template
+
+	// CollectTags specifies that rendered hashtags should be recorded
+	// for Tags to report after the document is converted.
+	//
+	// Defaults to not collecting tags.
+	CollectTags bool
 }
 
 var _ goldmark.Extender = (*Extender)(nil)
@@ -46,18 +154,38 @@ var _ goldmark.Extender = (*Extender)(nil)
 // Extend extends the provided goldmark Markdown object with support for
 // hashtags.
 func (e *Extender) Extend(m goldmark.Markdown) {
+	p := &Parser{
+		Variant:     e.Variant,
+		Frontmatter: e.Frontmatter,
+		Skip:        e.Skip,
+	}
 	m.Parser().AddOptions(
 		parser.WithInlineParsers(
-			util.Prioritized(&Parser{
-				Variant: e.Variant,
-			}, 999),
+			util.Prioritized(p, 999),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(p, 999),
 		),
 	)
 	m.Renderer().AddOptions(
 		renderer.WithNodeRenderers(
 			util.Prioritized(&Renderer{
-				Resolver:   e.Resolver,
-				Attributes: e.Attributes,
+				Resolver:          e.Resolver,
+				Attributes:        e.Attributes,
+				WrapperAttributes: e.WrapperAttributes,
+				OnResolveError:    e.OnResolveError,
+				ErrorClass:        e.ErrorClass,
+				MissingClass:      e.MissingClass,
+				Element:           e.Element,
+				Class:             e.Class,
+				LinkClass:         e.LinkClass,
+				NoWrapper:         e.NoWrapper,
+				AllowedSchemes:    e.AllowedSchemes,
+				Display:           e.Display,
+				Markup:            e.Markup,
+				Style:             e.Style,
+				Template:          e.Template,
+				CollectTags:       e.CollectTags,
 			}, 999),
 		),
 	)
@@ -0,0 +1,103 @@
+package hashtag
+
+import (
+	"encoding/xml"
+)
+
+// AtomCategory is an Atom category element.
+//
+//	<category term="foo" scheme="https://example.com/tags/" label="#foo"/>
+//
+// Embed these in the entries of an Atom feed built with encoding/xml.
+//
+//	type Entry struct {
+//		// ...
+//		Categories []hashtag.AtomCategory
+//	}
+type AtomCategory struct {
+	XMLName xml.Name `xml:"category"`
+
+	// Term is the canonical form of the hashtag.
+	Term string `xml:"term,attr"`
+
+	// Scheme identifies the categorization scheme of the term,
+	// e.g. the URL of the site's tag index.
+	//
+	// This is omitted if empty.
+	Scheme string `xml:"scheme,attr,omitempty"`
+
+	// Label is the hashtag with a leading "#".
+	Label string `xml:"label,attr,omitempty"`
+}
+
+// AtomCategories returns Atom category elements for the given tags.
+// scheme identifies the categorization scheme that the tags belong to
+// (see RFC 4287, section 4.2.2.2), e.g. the URL of the site's tag index.
+// It may be empty.
+//
+//	entry.Categories = hashtag.AtomCategories(
+//		hashtag.Tags(pc),
+//		"https://example.com/tags/",
+//	)
+func AtomCategories(tags []Tag, scheme string) []AtomCategory {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	cats := make([]AtomCategory, len(tags))
+	for i, t := range tags {
+		cats[i] = AtomCategory{
+			Term:   t.Name,
+			Scheme: scheme,
+			Label:  string(_hash) + t.Name,
+		}
+	}
+	return cats
+}
+
+// RSSCategory is an RSS 2.0 category element.
+//
+//	<category domain="https://example.com/tags/">foo</category>
+//
+// Embed these in the items of an RSS feed built with encoding/xml.
+//
+//	type Item struct {
+//		// ...
+//		Categories []hashtag.RSSCategory
+//	}
+type RSSCategory struct {
+	XMLName xml.Name `xml:"category"`
+
+	// Domain identifies the taxonomy of the category,
+	// e.g. the URL of the site's tag index.
+	//
+	// This is omitted if empty.
+	Domain string `xml:"domain,attr,omitempty"`
+
+	// Value is the canonical form of the hashtag.
+	Value string `xml:",chardata"`
+}
+
+// RSSCategories returns RSS category elements for the given tags.
+// domain identifies the taxonomy that the tags belong to,
+// e.g. the URL of the site's tag index.
+// It may be empty.
+//
+//	item.Categories = hashtag.RSSCategories(
+//		hashtag.Tags(pc),
+//		"https://example.com/tags/",
+//	)
+func RSSCategories(tags []Tag, domain string) []RSSCategory {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	cats := make([]RSSCategory, len(tags))
+	for i, t := range tags {
+		cats[i] = RSSCategory{
+			Domain: domain,
+			Value:  t.Name,
+		}
+	}
+	return cats
+}
@@ -0,0 +1,72 @@
+package hashtag
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomCategories(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		XMLName    xml.Name `xml:"entry"`
+		Categories []AtomCategory
+	}
+
+	got, err := xml.Marshal(entry{
+		Categories: AtomCategories([]Tag{
+			{Name: "foo", Destination: []byte("https://example.com/tags/foo")},
+			{Name: "bar"},
+		}, "https://example.com/tags?a=1&b=2"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `<entry>`+
+		`<category term="foo" scheme="https://example.com/tags?a=1&amp;b=2" label="#foo"></category>`+
+		`<category term="bar" scheme="https://example.com/tags?a=1&amp;b=2" label="#bar"></category>`+
+		`</entry>`, string(got))
+}
+
+func TestRSSCategories(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		XMLName    xml.Name `xml:"item"`
+		Categories []RSSCategory
+	}
+
+	got, err := xml.Marshal(item{
+		Categories: RSSCategories([]Tag{
+			{Name: "foo", Destination: []byte("https://example.com/tags/foo")},
+			{Name: "a<b"},
+		}, "https://example.com/tags/"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `<item>`+
+		`<category domain="https://example.com/tags/">foo</category>`+
+		`<category domain="https://example.com/tags/">a&lt;b</category>`+
+		`</item>`, string(got))
+}
+
+func TestFeedCategories_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, AtomCategories(nil, "https://example.com/tags/"))
+	assert.Nil(t, RSSCategories(nil, "https://example.com/tags/"))
+}
+
+func TestFeedCategories_NoScheme(t *testing.T) {
+	t.Parallel()
+
+	tags := []Tag{{Name: "foo", Destination: []byte("/tags/foo")}}
+
+	atom, err := xml.Marshal(AtomCategories(tags, ""))
+	require.NoError(t, err)
+	assert.Equal(t, `<category term="foo" label="#foo"></category>`, string(atom))
+
+	rss, err := xml.Marshal(RSSCategories(tags, ""))
+	require.NoError(t, err)
+	assert.Equal(t, `<category>foo</category>`, string(rss))
+}
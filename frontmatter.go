@@ -0,0 +1,110 @@
+package hashtag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// _frontmatterKeys are the metadata keys that list a document's tags.
+var _frontmatterKeys = []string{"tags", "tag"}
+
+// FrontmatterTags returns the tags listed in a document's metadata
+// under the "tags" or "tag" keys.
+// meta is the metadata as decoded from YAML,
+// e.g. by github.com/yuin/goldmark-meta.
+//
+// Tags may be listed as a YAML list or a comma-separated string.
+//
+//	tags: [foo, bar]
+//	tag: foo, bar
+//
+// Tag names are normalized with Canonical like inline hashtags,
+// so a leading "#" is optional.
+// The returned tags have the FrontmatterTag source.
+// They are not resolved, so they have no Destination
+// unless merged with an inline hashtag of the same name.
+//
+// Combine them with inline hashtags using MergeTags.
+//
+//	tags := hashtag.MergeTags(
+//		hashtag.FrontmatterTags(meta.Get(pc)),
+//		hashtag.Tags(pc),
+//	)
+func FrontmatterTags(meta map[string]any) []Tag {
+	var (
+		tags []Tag
+		seen = make(map[string]struct{})
+	)
+	add := func(name string) {
+		name = Canonical(strings.TrimSpace(name))
+		if name == "" {
+			return
+		}
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		tags = append(tags, Tag{Name: name, Source: FrontmatterTag})
+	}
+
+	for _, key := range _frontmatterKeys {
+		switch v := meta[key].(type) {
+		case string:
+			for _, name := range strings.Split(v, ",") {
+				add(name)
+			}
+		case []string:
+			for _, name := range v {
+				add(name)
+			}
+		case []any:
+			for _, item := range v {
+				if name, ok := frontmatterScalar(item); ok {
+					add(name)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// frontmatterScalar returns the string form of a scalar YAML value.
+// YAML decodes unquoted tags like "2024" as numbers.
+func frontmatterScalar(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case int, int64, uint64, float64:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+// MergeTags merges lists of tags into one,
+// keeping the first occurrence of each tag name.
+//
+// The sources of duplicate tags are combined,
+// and the first non-empty destination is kept.
+func MergeTags(lists ...[]Tag) []Tag {
+	var (
+		tags  []Tag
+		index = make(map[string]int) // name => index in tags
+	)
+	for _, list := range lists {
+		for _, t := range list {
+			i, ok := index[t.Name]
+			if !ok {
+				index[t.Name] = len(tags)
+				tags = append(tags, t)
+				continue
+			}
+
+			tags[i].Source |= t.Source
+			if len(tags[i].Destination) == 0 {
+				tags[i].Destination = t.Destination
+			}
+		}
+	}
+	return tags
+}
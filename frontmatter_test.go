@@ -0,0 +1,100 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+)
+
+func TestFrontmatterTags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give map[string]any
+		want []string
+	}{
+		{desc: "nil"},
+		{
+			desc: "no tags",
+			give: map[string]any{"title": "Hello"},
+		},
+		{
+			desc: "list",
+			give: map[string]any{"tags": []any{"Foo", "#bar", 2024}},
+			want: []string{"foo", "bar", "2024"},
+		},
+		{
+			desc: "string list",
+			give: map[string]any{"tags": []string{"foo", "bar"}},
+			want: []string{"foo", "bar"},
+		},
+		{
+			desc: "comma string",
+			give: map[string]any{"tags": "foo, #Bar ,, baz"},
+			want: []string{"foo", "bar", "baz"},
+		},
+		{
+			desc: "tag",
+			give: map[string]any{"tag": "foo"},
+			want: []string{"foo"},
+		},
+		{
+			desc: "tags and tag",
+			give: map[string]any{
+				"tags": []any{"foo", "bar"},
+				"tag":  "Foo, baz",
+			},
+			want: []string{"foo", "bar", "baz"},
+		},
+		{
+			desc: "unsupported values",
+			give: map[string]any{
+				"tags": []any{nil, map[string]any{"a": "b"}, "foo"},
+				"tag":  42,
+			},
+			want: []string{"foo"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, tag := range FrontmatterTags(tt.give) {
+				assert.Equal(t, FrontmatterTag, tag.Source)
+				got = append(got, tag.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		CollectTags: true,
+		Resolver:    prefixResolver{},
+	}))
+
+	pc := parser.NewContext()
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#Foo and #baz"), &buff, parser.WithContext(pc)))
+
+	got := MergeTags(
+		FrontmatterTags(map[string]any{"tags": []any{"foo", "bar"}}),
+		Tags(pc),
+	)
+	assert.Equal(t, []Tag{
+		{Name: "foo", Destination: []byte("/tags/Foo"), Source: FrontmatterTag | InlineTag},
+		{Name: "bar", Source: FrontmatterTag},
+		{Name: "baz", Destination: []byte("/tags/baz"), Source: InlineTag},
+	}, got)
+}
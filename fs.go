@@ -0,0 +1,111 @@
+package hashtag
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+// FSResolver is a Resolver that links hashtags to pages
+// only if the page exists in a file system.
+//
+// For example, the following links #foo to "/tags/foo/"
+// only if "content/tags/foo.md" exists.
+//
+//	&hashtag.FSResolver{
+//		FS:          os.DirFS("content"),
+//		Path:        "tags/{tag}.md",
+//		Destination: "/tags/{tag}/",
+//	}
+//
+// FSResolver caches the results of existence checks.
+// Use Refresh to clear the cache after the file system changes.
+type FSResolver struct {
+	// FS is the file system that holds pages for hashtags.
+	//
+	// This is required.
+	FS fs.FS
+
+	// Path is the pattern for paths of pages inside FS.
+	// All instances of "{tag}" in Path are replaced with the hashtag.
+	//
+	// Hashtags that do not form valid paths per fs.ValidPath
+	// will never be resolved.
+	//
+	// This is required.
+	Path string
+
+	// Destination is the pattern for links to pages.
+	// All instances of "{tag}" in Destination are replaced with the hashtag.
+	//
+	// Defaults to Path.
+	Destination string
+
+	mu     sync.RWMutex
+	exists map[string]bool // path => exists
+}
+
+var _ Resolver = (*FSResolver)(nil)
+
+// ResolveHashtag reports the destination for the given hashtag
+// if its page exists in the file system.
+func (r *FSResolver) ResolveHashtag(n *Node) ([]byte, error) {
+	if r.FS == nil {
+		return nil, errors.New("FSResolver: FS is not set")
+	}
+
+	tag := string(n.Tag)
+	path := strings.ReplaceAll(r.Path, "{tag}", tag)
+	if !fs.ValidPath(path) {
+		return nil, nil
+	}
+
+	ok, err := r.exist(path)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	dest := r.Destination
+	if dest == "" {
+		dest = r.Path
+	}
+	return []byte(strings.ReplaceAll(dest, "{tag}", tag)), nil
+}
+
+// Refresh clears the cache of existing pages.
+//
+// Call this after pages are added or removed from the file system.
+func (r *FSResolver) Refresh() {
+	r.mu.Lock()
+	r.exists = nil
+	r.mu.Unlock()
+}
+
+func (r *FSResolver) exist(path string) (bool, error) {
+	r.mu.RLock()
+	ok, cached := r.exists[path]
+	r.mu.RUnlock()
+	if cached {
+		return ok, nil
+	}
+
+	switch _, err := fs.Stat(r.FS, path); {
+	case err == nil:
+		ok = true
+	case errors.Is(err, fs.ErrNotExist):
+		ok = false
+	default:
+		// Don't cache errors that may be transient.
+		return false, fmt.Errorf("check page %q: %w", path, err)
+	}
+
+	r.mu.Lock()
+	if r.exists == nil {
+		r.exists = make(map[string]bool)
+	}
+	r.exists[path] = ok
+	r.mu.Unlock()
+	return ok, nil
+}
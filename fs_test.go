@@ -0,0 +1,111 @@
+package hashtag
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSResolver(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"tags/foo.md":     {},
+		"tags/foo/bar.md": {},
+	}
+
+	tests := []struct {
+		desc        string
+		tag         string
+		destination string
+		want        string
+	}{
+		{desc: "exists", tag: "foo", want: "tags/foo.md"},
+		{desc: "nested", tag: "foo/bar", want: "tags/foo/bar.md"},
+		{desc: "does not exist", tag: "bar"},
+		{desc: "invalid path", tag: "../foo"},
+		{
+			desc:        "destination",
+			tag:         "foo",
+			destination: "/t/{tag}/",
+			want:        "/t/foo/",
+		},
+		{
+			desc:        "destination does not exist",
+			tag:         "bar",
+			destination: "/t/{tag}/",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r := FSResolver{
+				FS:          fsys,
+				Path:        "tags/{tag}.md",
+				Destination: tt.destination,
+			}
+			got, err := r.ResolveHashtag(&Node{Tag: []byte(tt.tag)})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestFSResolver_Refresh(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{}
+	r := FSResolver{FS: fsys, Path: "{tag}.md"}
+	node := &Node{Tag: []byte("foo")}
+
+	got, err := r.ResolveHashtag(node)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	fsys["foo.md"] = &fstest.MapFile{}
+
+	got, err = r.ResolveHashtag(node)
+	require.NoError(t, err)
+	assert.Empty(t, got, "should use cached result")
+
+	r.Refresh()
+
+	got, err = r.ResolveHashtag(node)
+	require.NoError(t, err)
+	assert.Equal(t, "foo.md", string(got))
+}
+
+func TestFSResolver_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no FS", func(t *testing.T) {
+		t.Parallel()
+
+		var r FSResolver
+		_, err := r.ResolveHashtag(&Node{Tag: []byte("foo")})
+		assert.ErrorContains(t, err, "FS is not set")
+	})
+
+	t.Run("stat error", func(t *testing.T) {
+		t.Parallel()
+
+		giveErr := errors.New("great sadness")
+		r := FSResolver{FS: errFS{Err: giveErr}, Path: "{tag}.md"}
+		_, err := r.ResolveHashtag(&Node{Tag: []byte("foo")})
+		assert.ErrorIs(t, err, giveErr)
+		assert.ErrorContains(t, err, `check page "foo.md"`)
+	})
+}
+
+type errFS struct{ Err error }
+
+func (f errFS) Open(string) (fs.File, error) {
+	return nil, f.Err
+}
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/require"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
 	"go.abhg.dev/goldmark/hashtag"
 	"gopkg.in/yaml.v3"
 )
@@ -52,22 +55,122 @@ func TestIntegration_Attributes(t *testing.T) {
 		})))
 }
 
-func testIntegration(t *testing.T, file string, md goldmark.Markdown) {
+func TestIntegration_WrapperAttributes(t *testing.T) {
+	t.Parallel()
+
+	testIntegration(t, "wrapper_attributes.yaml",
+		goldmark.New(goldmark.WithExtensions(&hashtag.Extender{
+			Resolver: almostAlwaysResolver{},
+			WrapperAttributes: []hashtag.Attribute{
+				{
+					Name: "data-tag",
+					ValueFunc: func(n *hashtag.Node) string {
+						return string(n.Tag)
+					},
+				},
+				{
+					Name:  "itemprop",
+					Value: "keywords",
+				},
+			},
+		})))
+}
+
+func TestIntegration_Display(t *testing.T) {
+	t.Parallel()
+
+	testIntegration(t, "display.yaml",
+		goldmark.New(goldmark.WithExtensions(&hashtag.Extender{
+			Resolver: almostAlwaysResolver{},
+			Display: func(n *hashtag.Node) string {
+				// Show only the last level of the hierarchy,
+				// and split camelCase words.
+				tag := string(n.Tag)
+				if idx := strings.LastIndexByte(tag, '/'); idx >= 0 {
+					tag = tag[idx+1:]
+				}
+
+				var sb strings.Builder
+				sb.WriteByte('#')
+				for _, r := range tag {
+					if unicode.IsUpper(r) {
+						sb.WriteByte(' ')
+						r = unicode.ToLower(r)
+					}
+					sb.WriteRune(r)
+				}
+				return sb.String()
+			},
+		})))
+}
+
+func TestIntegration_Markup(t *testing.T) {
+	t.Parallel()
+
+	testIntegrationExtender(t, "markup.yaml", hashtag.Extender{
+		Resolver: almostAlwaysResolver{},
+		Markup:   hashtag.MicroformatsMarkup | hashtag.MicrodataMarkup,
+	})
+}
+
+func TestIntegration_Missing(t *testing.T) {
+	t.Parallel()
+
+	testIntegration(t, "missing.yaml",
+		goldmark.New(goldmark.WithExtensions(&hashtag.Extender{
+			Resolver:     wikiResolver{},
+			MissingClass: "red-link",
+		})))
+}
+
+// integrationTest is a test case in a testdata file.
+type integrationTest struct {
+	Desc string `yaml:"desc"`
+	Give string `yaml:"give"`
+	Want string `yaml:"want"`
+
+	// NoWrapper sets Extender.NoWrapper for this case.
+	// Only supported by testIntegrationExtender.
+	NoWrapper bool `yaml:"noWrapper"`
+}
+
+func readIntegrationTests(t *testing.T, file string) []integrationTest {
 	testsdata, err := os.ReadFile(filepath.Join("testdata", file))
 	require.NoError(t, err)
 
-	var tests []struct {
-		Desc string `yaml:"desc"`
-		Give string `yaml:"give"`
-		Want string `yaml:"want"`
-	}
+	var tests []integrationTest
 	require.NoError(t, yaml.Unmarshal(testsdata, &tests))
+	return tests
+}
 
-	for _, tt := range tests {
+func testIntegration(t *testing.T, file string, md goldmark.Markdown) {
+	for _, tt := range readIntegrationTests(t, file) {
 		tt := tt
 		t.Run(tt.Desc, func(t *testing.T) {
 			t.Parallel()
 
+			require.False(t, tt.NoWrapper, "noWrapper requires testIntegrationExtender")
+
+			var buf bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.Give), &buf))
+			require.Equal(t, tt.Want, buf.String())
+		})
+	}
+}
+
+// testIntegrationExtender is like testIntegration,
+// but builds the Markdown from ext for each case
+// so that cases may override its options.
+func testIntegrationExtender(t *testing.T, file string, ext hashtag.Extender) {
+	for _, tt := range readIntegrationTests(t, file) {
+		tt := tt
+		t.Run(tt.Desc, func(t *testing.T) {
+			t.Parallel()
+
+			ext := ext
+			ext.NoWrapper = tt.NoWrapper
+			md := goldmark.New(goldmark.WithExtensions(&ext))
+
 			var buf bytes.Buffer
 			require.NoError(t, md.Convert([]byte(tt.Give), &buf))
 			require.Equal(t, tt.Want, buf.String())
@@ -87,3 +190,29 @@ func (almostAlwaysResolver) ResolveHashtag(n *hashtag.Node) ([]byte, error) {
 
 	return append([]byte("/tag/"), n.Tag...), nil
 }
+
+// Resolves tags like a wiki:
+// "missing" and "gone" don't have pages,
+// and only "missing" may have one created.
+type wikiResolver struct{}
+
+var _ hashtag.DetailedResolver = wikiResolver{}
+
+func (wikiResolver) ResolveHashtag(n *hashtag.Node) ([]byte, error) {
+	return almostAlwaysResolver{}.ResolveHashtag(n)
+}
+
+func (wikiResolver) ResolveHashtagDetails(_ parser.Context, n *hashtag.Node) (hashtag.Resolution, error) {
+	switch string(n.Tag) {
+	case "missing":
+		return hashtag.Resolution{
+			Destination: append([]byte("/new?tag="), n.Tag...),
+			Missing:     true,
+		}, nil
+	case "gone":
+		return hashtag.Resolution{Missing: true}, nil
+	}
+
+	dest, err := almostAlwaysResolver{}.ResolveHashtag(n)
+	return hashtag.Resolution{Destination: dest}, err
+}
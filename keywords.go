@@ -0,0 +1,43 @@
+package hashtag
+
+import (
+	"html/template"
+	"strings"
+)
+
+// Keywords returns the names of the given tags.
+//
+// Use this for the "keywords" property of JSON-LD metadata.
+//
+//	article := map[string]any{
+//		"@context": "https://schema.org",
+//		"@type":    "BlogPosting",
+//		"keywords": hashtag.Keywords(hashtag.Tags(pc)),
+//	}
+func Keywords(tags []Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// MetaKeywords returns an HTML meta element listing the given tags.
+//
+//	<meta name="keywords" content="foo, bar">
+//
+// It returns an empty string if there are no tags.
+// The result is safe to use inside an html/template.
+func MetaKeywords(tags []Tag) template.HTML {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	content := strings.Join(Keywords(tags), ", ")
+	return template.HTML(`<meta name="keywords" content="` +
+		template.HTMLEscapeString(content) + `">`)
+}
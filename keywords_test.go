@@ -0,0 +1,54 @@
+package hashtag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywords(t *testing.T) {
+	t.Parallel()
+
+	tags := []Tag{
+		{Name: "foo", Destination: []byte("/tags/foo")},
+		{Name: "bar"},
+	}
+	assert.Equal(t, []string{"foo", "bar"}, Keywords(tags))
+
+	got, err := json.Marshal(map[string]any{"keywords": Keywords(tags)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"keywords": ["foo", "bar"]}`, string(got))
+}
+
+func TestMetaKeywords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give []Tag
+		want string
+	}{
+		{desc: "empty"},
+		{
+			desc: "tags",
+			give: []Tag{{Name: "foo"}, {Name: "bar"}},
+			want: `<meta name="keywords" content="foo, bar">`,
+		},
+		{
+			desc: "escaped",
+			give: []Tag{{Name: `"><script>`}},
+			want: `<meta name="keywords" content="&#34;&gt;&lt;script&gt;">`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, string(MetaKeywords(tt.give)))
+		})
+	}
+}
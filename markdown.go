@@ -0,0 +1,52 @@
+package hashtag
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// MarkdownRenderer renders hashtag nodes back into Markdown.
+//
+// Use this with goldmark renderers that produce Markdown
+// (e.g. to format Markdown documents)
+// to write hashtags exactly as they appear in the source document.
+//
+//	renderer.WithNodeRenderers(
+//		util.Prioritized(&hashtag.MarkdownRenderer{}, 999),
+//	)
+type MarkdownRenderer struct{}
+
+var _ renderer.NodeRenderer = (*MarkdownRenderer)(nil)
+
+// RegisterFuncs registers rendering functions from this renderer onto the
+// provided registerer.
+func (r *MarkdownRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(Kind, r.Render)
+}
+
+// Render renders a hashtag node as Markdown.
+func (r *MarkdownRenderer) Render(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n, ok := node.(*Node)
+	if !ok {
+		return ast.WalkStop, fmt.Errorf("unexpected node %T, expected *Node", node)
+	}
+
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.Write(sourceText(n, src))
+	return ast.WalkSkipChildren, nil
+}
+
+// sourceText returns the text of the hashtag as it appears in the document,
+// or "#" followed by the tag for nodes that were not parsed from it.
+func sourceText(n *Node, src []byte) []byte {
+	if text := nodeText(n, src); len(text) > 0 {
+		return text
+	}
+	return append([]byte{_hash}, n.Tag...)
+}
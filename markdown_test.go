@@ -0,0 +1,101 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestMarkdownRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc    string
+		give    string
+		variant Variant
+	}{
+		{desc: "simple", give: "Foo #bar # baz."},
+		{desc: "hierarchy", give: "Filed under #a/b-c_d.\n#e at line start"},
+		{desc: "unicode", give: "#éabc and #日本語"},
+		{desc: "obsidian", give: "#123tag #✅/🚧 #tag%tag", variant: ObsidianVariant},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{Variant: tt.variant}),
+				goldmark.WithRenderer(renderer.NewRenderer(
+					renderer.WithNodeRenderers(
+						util.Prioritized(sourceTextRenderer{}, 1000),
+						util.Prioritized(&MarkdownRenderer{}, 999),
+					),
+				)),
+			)
+
+			src := []byte(tt.give)
+			doc := md.Parser().Parse(text.NewReader(src))
+
+			var tags int
+			require.NoError(t, ast.Walk(doc, func(n ast.Node, enter bool) (ast.WalkStatus, error) {
+				if enter && n.Kind() == Kind {
+					tags++
+				}
+				return ast.WalkContinue, nil
+			}))
+			require.NotZero(t, tags, "document must have hashtags")
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Renderer().Render(&buff, src, doc))
+			assert.Equal(t, tt.give, buff.String())
+		})
+	}
+}
+
+func TestMarkdownRenderer_NoSource(t *testing.T) {
+	t.Parallel()
+
+	r := renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&MarkdownRenderer{}, 999),
+		),
+	)
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Render(&buff, nil, &Node{Tag: []byte("foo")}))
+	assert.Equal(t, "#foo", buff.String())
+}
+
+func TestMarkdownRenderer_WrongNode(t *testing.T) {
+	t.Parallel()
+
+	var r MarkdownRenderer
+	_, err := r.Render(nil, nil, ast.NewText(), true)
+	assert.ErrorContains(t, err, "unexpected node *ast.Text")
+}
+
+// sourceTextRenderer is a minimal renderer for the nodes of a single
+// paragraph that writes text exactly as it appears in the source.
+type sourceTextRenderer struct{}
+
+func (sourceTextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindText, func(w util.BufWriter, src []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			t := n.(*ast.Text)
+			_, _ = w.Write(t.Segment.Value(src))
+			if t.SoftLineBreak() {
+				_ = w.WriteByte('\n')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
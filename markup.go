@@ -0,0 +1,62 @@
+package hashtag
+
+// Markup is a set of semantic markup conventions
+// that the Renderer follows for hashtags.
+//
+// Combine multiple conventions with '|'.
+//
+//	hashtag.MicroformatsMarkup | hashtag.MicrodataMarkup
+type Markup uint
+
+const (
+	// MicroformatsMarkup marks hashtags as microformats2 categories
+	// (https://microformats.org/wiki/h-entry).
+	//
+	// Links to hashtags get class="p-category" and rel="tag".
+	// Hashtags without links get class="p-category" on their element.
+	// If NoWrapper is set, hashtags without links
+	// are wrapped in a bare element to hold the class.
+	MicroformatsMarkup Markup = 1 << iota
+
+	// MicrodataMarkup marks hashtags as schema.org keywords
+	// with microdata: itemprop="keywords" on the hashtag's element.
+	//
+	// This has no effect if NoWrapper is set
+	// because itemprop on a link refers to its destination,
+	// and it doesn't cause hashtags without links to be wrapped.
+	MicrodataMarkup
+
+	// RDFaMarkup marks hashtags as schema.org keywords
+	// with RDFa: property="keywords" on the hashtag's element.
+	//
+	// This has no effect if NoWrapper is set
+	// because property on a link refers to its destination,
+	// and it doesn't cause hashtags without links to be wrapped.
+	RDFaMarkup
+)
+
+// wrapperAttributes returns attributes for the element wrapping a hashtag.
+func (m Markup) wrapperAttributes(hasLink bool) []Attribute {
+	var attrs []Attribute
+	if m&MicroformatsMarkup != 0 && !hasLink {
+		attrs = append(attrs, Attribute{Name: "class", Value: "p-category"})
+	}
+	if m&MicrodataMarkup != 0 {
+		attrs = append(attrs, Attribute{Name: "itemprop", Value: "keywords"})
+	}
+	if m&RDFaMarkup != 0 {
+		attrs = append(attrs, Attribute{Name: "property", Value: "keywords"})
+	}
+	return attrs
+}
+
+// linkAttributes returns attributes for a hashtag's link.
+func (m Markup) linkAttributes() []Attribute {
+	if m&MicroformatsMarkup == 0 {
+		return nil
+	}
+	return []Attribute{
+		{Name: "class", Value: "p-category"},
+		{Name: "rel", Value: "tag"},
+	}
+}
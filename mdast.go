@@ -0,0 +1,77 @@
+package hashtag
+
+import (
+	"github.com/yuin/goldmark/ast"
+)
+
+// MdastType is the mdast node type of hashtags.
+const MdastType = "hashtag"
+
+// MdastHashtag is the mdast (https://github.com/syntax-tree/mdast)
+// representation of a hashtag.
+// It encodes to JSON as follows.
+//
+//	{
+//	  "type": "hashtag",
+//	  "value": "foo",
+//	  "position": {
+//	    "start": {"line": 1, "column": 5, "offset": 4},
+//	    "end": {"line": 1, "column": 9, "offset": 8}
+//	  }
+//	}
+//
+// Use Mdast to build one from a Node
+// when exporting goldmark documents to mdast.
+type MdastHashtag struct {
+	// Type is always MdastType.
+	Type string `json:"type"`
+
+	// Value is the tag without the leading "#".
+	Value string `json:"value"`
+
+	// Position is the location of the hashtag in the source document,
+	// including the leading "#".
+	//
+	// This is nil if the hashtag was not parsed from the document.
+	Position *MdastPosition `json:"position,omitempty"`
+}
+
+// MdastPosition is the location of an mdast node in the source document.
+//
+// Columns count bytes, not characters.
+type MdastPosition struct {
+	// Start is the position of the first byte of the node.
+	Start Position `json:"start"`
+
+	// End is the position just past the last byte of the node.
+	End Position `json:"end"`
+}
+
+// Mdast returns the mdast representation of the given hashtag.
+// src is the source document the hashtag was parsed from.
+func Mdast(n *Node, src []byte) *MdastHashtag {
+	m := &MdastHashtag{
+		Type:  MdastType,
+		Value: string(n.Tag),
+	}
+
+	start, stop := nodeOffset(n), nodeStop(n)
+	if start >= 0 && stop >= start && stop <= len(src) {
+		m.Position = &MdastPosition{
+			Start: positionOf(src, start),
+			End:   positionOf(src, stop),
+		}
+	}
+	return m
+}
+
+// nodeStop returns the offset just past the end of the hashtag
+// in the source document, or -1 if it's not known.
+func nodeStop(n *Node) int {
+	for c := n.LastChild(); c != nil; c = c.PreviousSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			return t.Segment.Stop
+		}
+	}
+	return -1
+}
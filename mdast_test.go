@@ -0,0 +1,49 @@
+package hashtag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestMdast(t *testing.T) {
+	t.Parallel()
+
+	src := []byte("Foo\n\nbar #baz/qux.")
+	doc := goldmark.New(goldmark.WithExtensions(&Extender{})).
+		Parser().
+		Parse(text.NewReader(src))
+
+	var tags []*Node
+	require.NoError(t, ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if n, ok := n.(*Node); ok && entering {
+			tags = append(tags, n)
+		}
+		return ast.WalkContinue, nil
+	}))
+	require.Len(t, tags, 1)
+
+	got, err := json.Marshal(Mdast(tags[0], src))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "hashtag",
+		"value": "baz/qux",
+		"position": {
+			"start": {"line": 3, "column": 5, "offset": 9},
+			"end": {"line": 3, "column": 13, "offset": 17}
+		}
+	}`, string(got))
+}
+
+func TestMdast_NoSource(t *testing.T) {
+	t.Parallel()
+
+	got, err := json.Marshal(Mdast(&Node{Tag: []byte("foo")}, nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "hashtag", "value": "foo"}`, string(got))
+}
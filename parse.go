@@ -23,6 +23,36 @@ type Parser struct {
 	// Defaults to DefaultVariant. See the documentation of individual
 	// variants for more information.
 	Variant Variant
+
+	// Frontmatter, if set, returns the metadata of the document
+	// parsed with the given parser.Context.
+	// Documents may choose their hashtag syntax with a "hashtag" key
+	// in their metadata. See Syntax for details.
+	//
+	// For example, with github.com/yuin/goldmark-meta:
+	//
+	//	&hashtag.Parser{Frontmatter: meta.Get}
+	Frontmatter func(parser.Context) map[string]any
+
+	// Skip lists the kinds of ancestors
+	// inside which hashtags are not recognized.
+	//
+	// LinkAncestor and EmphasisAncestor are built
+	// only after the text inside them is parsed.
+	// Hashtags inside them are removed from the AST
+	// only if the Parser is also registered as an AST transformer,
+	// as Extender does. See Transform.
+	// Otherwise, Renderer and ANSIRenderer render them as plain text.
+	//
+	//	p := &hashtag.Parser{}
+	//	md.Parser().AddOptions(
+	//		parser.WithInlineParsers(util.Prioritized(p, 999)),
+	//		parser.WithASTTransformers(util.Prioritized(p, 999)),
+	//	)
+	//
+	// Defaults to DefaultSkip, which skips links.
+	// Set to NoAncestor to recognize hashtags everywhere.
+	Skip Ancestor
 }
 
 // Variant represents one of the different flavours of hashtag syntax.
@@ -111,7 +141,7 @@ func (*Parser) Trigger() []byte {
 }
 
 // Parse parses a hashtag node.
-func (p *Parser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+func (p *Parser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	line, seg := block.PeekLine()
 
 	if len(line) == 0 || line[0] != _hash {
@@ -119,14 +149,29 @@ func (p *Parser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node
 	}
 	line = line[1:]
 
-	end := p.Variant.span(line)
+	doc := p.document(parent, block, pc)
+	if doc.disabled(parent, seg.Start) {
+		return nil
+	}
+
+	// Inline ancestors like links don't exist yet.
+	// Those are handled by Transform.
+	skip := p.skip()
+	if skip.skips(parent) {
+		return nil
+	}
+
+	end := doc.syntax.Variant.span(line)
 	if end < 0 {
 		return nil
 	}
 	seg = seg.WithStop(seg.Start + end + 1) // + '#'
 
 	n := Node{
-		Tag: block.Value(seg.WithStart(seg.Start + 1)), // omit the "#"
+		Tag:     block.Value(seg.WithStart(seg.Start + 1)), // omit the "#"
+		context: pc,
+		report:  reportFor(pc),
+		skip:    skip,
 	}
 	n.AppendChild(&n, ast.NewTextSegment(seg))
 	block.Advance(seg.Len())
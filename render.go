@@ -1,22 +1,18 @@
 package hashtag
 
 import (
+	"bytes"
 	"fmt"
-	"sync"
+	"html/template"
+	"slices"
+	"strings"
 
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/util"
 )
 
-// Resolver resolves hashtags to pages they should link to.
-type Resolver interface {
-	// ResolveHashtag reports the link that the provided hashtag Node
-	// should point to, or an empty destination for hashtags that should
-	// not link to anything.
-	ResolveHashtag(*Node) (destination []byte, err error)
-}
-
 // Renderer renders hashtag nodes into HTML, optionally linking them to
 // specific pages.
 //
@@ -30,29 +26,172 @@ type Resolver interface {
 // the following.
 //
 //	<span class="hashtag"><a href="...">#foo</a></span>
+//
+// Attributes set on the Node (e.g. by an AST transformer)
+// are rendered on the element that wraps the hashtag.
+//
+// Renderer honors goldmark's HTML rendering options
+// (e.g. html.WithUnsafe) when it's installed with an Extender
+// or registered with a goldmark renderer.
 type Renderer struct {
+	html.Config
+
 	// Resolver specifies how where hashtag links should point, if at all.
 	//
+	// If the Resolver implements ContextResolver,
+	// it will also receive the parser.Context of the document.
+	// If the Resolver implements DetailedResolver,
+	// it may also specify per-tag attributes and classes.
+	//
 	// When a Resolver returns an empty destination for a hashtag, the
 	// Renderer will render the hashtag as plain text rather than a link.
 	//
 	// Defaults to empty destinations for all hashtags.
 	Resolver Resolver
 
+	// Attributes are added to the <a> tag.
+	//
+	// Attributes will only be applied if the tag can be resolved by the Resolver.
+	// Per-tag attributes reported by a DetailedResolver are merged with these.
+	//
+	// Rendering fails if these are invalid per ValidateAttributes.
+	// Invalid per-tag attributes are handled per OnResolveError.
 	Attributes []Attribute
 
-	hasDest sync.Map // *Node => struct{}
+	// WrapperAttributes are added to the element that wraps hashtags.
+	//
+	// These are applied to all hashtags, whether they resolve or not.
+	// If NoWrapper is set, these are added to the <a> tag instead.
+	//
+	// Rendering fails if these are invalid per ValidateAttributes.
+	WrapperAttributes []Attribute
+
+	// OnResolveError specifies how to handle errors from the Resolver.
+	//
+	// Errors are recorded in the document's parser.Context
+	// regardless of this setting. Use ResolveErrors to retrieve them.
+	//
+	// Defaults to AbortOnResolveError.
+	OnResolveError ResolveErrorPolicy
+
+	// ErrorClass is the class added to hashtags that failed to resolve
+	// when OnResolveError is ErrorClassOnResolveError.
+	//
+	// Defaults to DefaultErrorClass.
+	ErrorClass string
+
+	// MissingClass is the class added to hashtags
+	// that a DetailedResolver reported as missing.
+	//
+	// Defaults to DefaultMissingClass.
+	MissingClass string
+
+	// Element is the name of the HTML element that wraps hashtags.
+	//
+	// Defaults to DefaultElement.
+	Element string
+
+	// Class is the class of the element that wraps hashtags.
+	//
+	// Defaults to DefaultClass.
+	Class string
+
+	// LinkClass is the class of the <a> tag for hashtags with a destination.
+	//
+	// Defaults to no class.
+	LinkClass string
+
+	// NoWrapper specifies that hashtags should not be wrapped in an element.
+	//
+	// If set, hashtags with a destination are rendered as just an <a> tag,
+	// and hashtags without one are rendered as plain text.
+	// Classes and attributes meant for the wrapper
+	// (per-tag classes, WrapperAttributes, node attributes, and Style)
+	// are added to the <a> tag instead.
+	// Hashtags without a destination that have any of these,
+	// or the MicroformatsMarkup class,
+	// are wrapped in an Element without Class to keep them.
+	NoWrapper bool
+
+	// AllowedSchemes are the URL schemes allowed in destinations
+	// reported by the Resolver.
+	// Relative URLs are always allowed.
+	//
+	// Hashtags with destinations that use other schemes,
+	// or that goldmark considers dangerous (see html.IsDangerousURL),
+	// are rendered as plain text.
+	// This check is skipped if the Unsafe option is set.
+	//
+	// Defaults to "http" and "https".
+	AllowedSchemes []string
+
+	// Display, if set, reports the text displayed for a hashtag
+	// in place of the hashtag as written in the document.
+	//
+	// For example, the following displays hashtags without the "#".
+	//
+	//	Display: func(n *hashtag.Node) string {
+	//		return string(n.Tag)
+	//	}
+	//
+	// This does not affect the Node's Tag,
+	// which is still used to resolve the hashtag.
+	//
+	// Defaults to the hashtag as written in the document.
+	Display func(*Node) string
+
+	// Markup specifies the semantic markup conventions to follow
+	// for hashtags (e.g. microformats2 or schema.org microdata).
+	//
+	// Defaults to no semantic markup.
+	Markup Markup
+
+	// Style, if set, assigns colours and icons to hashtags.
+	//
+	// Colours are applied to the element that wraps the hashtag,
+	// or the <a> tag if NoWrapper is set.
+	// Icons are displayed before the hashtag.
+	Style *Style
+
+	// Template, if set, renders hashtags instead of the default HTML.
+	//
+	// The template is executed with a *TemplateData for each hashtag,
+	// and its output replaces the entire hashtag.
+	// Element, Class, LinkClass, NoWrapper, WrapperAttributes, and Markup
+	// are ignored if Template is set.
+	Template *template.Template
+
+	// CollectTags specifies that rendered hashtags should be recorded
+	// for Tags to report after the document is rendered.
+	//
+	// Defaults to not collecting tags.
+	CollectTags bool
 }
 
-// Attribute defines an attribute to be added to an HTML tag.
-//
-//	Attribute{ Attr: "class", Value: "tag"}
+const (
+	// DefaultElement is the default name of the HTML element
+	// that wraps hashtags.
+	DefaultElement = "span"
+
+	// DefaultClass is the default class of the HTML element
+	// that wraps hashtags.
+	DefaultClass = "hashtag"
+)
+
+// DefaultMissingClass is the class added to hashtags
+// that a DetailedResolver reported as missing.
+const DefaultMissingClass = "hashtag-missing"
+
+var (
+	_ renderer.NodeRenderer = (*Renderer)(nil)
+	_ renderer.SetOptioner  = (*Renderer)(nil)
+)
+
+// AttributeFilter defines the attribute names
+// that the Renderer will render from hashtag nodes.
 //
-// Will result in <a class="tag" ...>
-type Attribute struct {
-	Name  string
-	Value string
-}
+// Attributes with names starting with "data-" are always rendered.
+var AttributeFilter = html.GlobalAttributeFilter
 
 // RegisterFuncs registers rendering functions from this renderer onto the
 // provided registerer.
@@ -61,56 +200,261 @@ func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 }
 
 // Render renders a hashtag node as HTML.
-func (r *Renderer) Render(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+func (r *Renderer) Render(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
 	n, ok := node.(*Node)
 	if !ok {
 		return ast.WalkStop, fmt.Errorf("unexpected node %T, expected *Node", node)
 	}
 
-	if entering {
-		if err := r.enter(w, n); err != nil {
-			return ast.WalkStop, err
+	// Hashtags are rendered entirely upon entering the node
+	// so that we don't need to track state between enter and exit.
+	// Hashtags that the Parser skips render as their text.
+	if !entering || n.skipped() {
+		return ast.WalkContinue, nil
+	}
+
+	render := r.render
+	if r.Template != nil {
+		render = r.renderTemplate
+	}
+	if err := render(w, src, n); err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// resolve resolves the given hashtag with the Renderer's Resolver,
+// handling errors per OnResolveError.
+//
+// It returns the resolution and the classes for the hashtag,
+// not including the Renderer's Class.
+func (r *Renderer) resolve(src []byte, n *Node) (res Resolution, classes []string, err error) {
+	res, err = resolve(r.Resolver, n)
+	if err == nil {
+		err = ValidateAttributes(res.Attributes)
+	}
+	classes = slices.Clip(res.Classes) // don't modify the resolver's slice
+	if res.Missing {
+		missingClass := r.MissingClass
+		if missingClass == "" {
+			missingClass = DefaultMissingClass
+		}
+		classes = append(classes, missingClass)
+	}
+	if err != nil {
+		rerr := reportResolveError(src, n, err)
+		switch r.OnResolveError {
+		case PlainTextOnResolveError:
+			// Use the classes that were resolved, if any.
+		case ErrorClassOnResolveError:
+			errorClass := r.ErrorClass
+			if errorClass == "" {
+				errorClass = DefaultErrorClass
+			}
+			classes = append(classes, errorClass)
+		default:
+			return res, classes, rerr
+		}
+		res.Destination = nil
+	}
+
+	if len(res.Destination) > 0 && !r.Unsafe && !isAllowedDestination(res.Destination, r.AllowedSchemes) {
+		res.Destination = nil
+	}
+
+	if r.CollectTags && n.report != nil {
+		n.report.addTag(n, res.Destination)
+	}
+
+	return res, classes, nil
+}
+
+// validate reports errors in the Renderer's configuration.
+func (r *Renderer) validate() error {
+	if err := ValidateAttributes(r.Attributes); err != nil {
+		return fmt.Errorf("invalid Attributes: %w", err)
+	}
+	if err := ValidateAttributes(r.WrapperAttributes); err != nil {
+		return fmt.Errorf("invalid WrapperAttributes: %w", err)
+	}
+	return nil
+}
+
+func (r *Renderer) render(w util.BufWriter, src []byte, n *Node) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	res, classes, err := r.resolve(src, n)
+	if err != nil {
+		return err
+	}
+
+	hasLink := len(res.Destination) > 0
+
+	wrapperAttrs := r.Style.attributes(string(n.Tag))
+	if !r.NoWrapper {
+		wrapperAttrs = mergeAttributes(wrapperAttrs, r.Markup.wrapperAttributes(hasLink))
+	} else if !hasLink {
+		// Microdata and RDFa markup belongs only on a wrapper,
+		// so it doesn't warrant one.
+		// The microformats class is kept on a bare wrapper.
+		markup := r.Markup & MicroformatsMarkup
+		wrapperAttrs = mergeAttributes(wrapperAttrs, markup.wrapperAttributes(hasLink))
+	}
+	wrapperAttrs = mergeAttributes(wrapperAttrs, expandAttributes(r.WrapperAttributes, n))
+	wrapperAttrs = mergeAttributes(wrapperAttrs, nodeAttributes(n))
+
+	linkAttrs := r.Markup.linkAttributes()
+	linkAttrs = mergeAttributes(linkAttrs, expandAttributes(r.Attributes, n))
+	linkAttrs = mergeAttributes(linkAttrs, expandAttributes(res.Attributes, n))
+
+	var linkClasses []string
+	wrap := !r.NoWrapper
+	class := r.Class
+	if class == "" {
+		class = DefaultClass
+	}
+	if r.NoWrapper {
+		if hasLink {
+			// Without a wrapper, the link is the only place
+			// for classes and attributes of the wrapper.
+			linkClasses = classes
+			linkAttrs = mergeAttributes(linkAttrs, wrapperAttrs)
+		} else if len(classes) > 0 || len(wrapperAttrs) > 0 {
+			// Without a link, use a bare wrapper
+			// so that per-tag classes and attributes aren't lost.
+			wrap = true
+			class = ""
 		}
-	} else {
-		r.exit(w, n)
 	}
+	if wrap {
+		writeStartTag(w, r.element(), class, classes, wrapperAttrs)
+		_ = w.WriteByte('>')
+	}
+
+	if hasLink {
+		writeStartTag(w, "a", r.LinkClass, linkClasses, linkAttrs)
+		_, _ = w.WriteString(` href="`)
+		_, _ = w.Write(util.URLEscape(res.Destination, true /* resolve references */))
+		_, _ = w.WriteString(`">`)
+	}
+
+	r.writeText(w, src, n)
 
-	return ast.WalkContinue, nil
+	if hasLink {
+		_, _ = w.WriteString("</a>")
+	}
+	if wrap {
+		_, _ = w.WriteString("</")
+		_, _ = w.WriteString(r.element())
+		_ = w.WriteByte('>')
+	}
+	return nil
 }
 
-func (r *Renderer) enter(w util.BufWriter, n *Node) error {
-	_, _ = w.WriteString(`<span class="hashtag">`)
+// writeText writes the text of the hashtag.
+func (r *Renderer) writeText(w util.BufWriter, src []byte, n *Node) {
+	if icon := r.Style.Icon(string(n.Tag)); icon != "" {
+		_, _ = w.Write(util.EscapeHTML(util.StringToReadOnlyBytes(icon)))
+	}
+
+	if r.Display != nil {
+		_, _ = w.Write(util.EscapeHTML(util.StringToReadOnlyBytes(r.Display(n))))
+		return
+	}
+
+	writer := r.Writer
+	if writer == nil {
+		writer = html.DefaultWriter
+	}
 
-	var dest []byte
-	if res := r.Resolver; res != nil {
-		var err error
-		dest, err = res.ResolveHashtag(n)
-		if err != nil {
-			return fmt.Errorf("resolve hashtag %q: %w", n.Tag, err)
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			writer.Write(w, c.Segment.Value(src))
+		case *ast.String:
+			writer.Write(w, c.Value)
 		}
 	}
+}
+
+func (r *Renderer) element() string {
+	if r.Element == "" {
+		return DefaultElement
+	}
+	return r.Element
+}
+
+// writeStartTag writes the start of an HTML tag
+// with the given classes and attributes, without the closing '>'.
+//
+// class, classes, and the values of all "class" attributes in attrs
+// are combined into a single class attribute.
+func writeStartTag(w util.BufWriter, name, class string, classes []string, attrs []Attribute) {
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(name)
 
-	if len(dest) == 0 {
-		return nil
+	var hasClass bool
+	writeClass := func(c string) {
+		if c == "" {
+			return
+		}
+		if hasClass {
+			_ = w.WriteByte(' ')
+		} else {
+			_, _ = w.WriteString(` class="`)
+			hasClass = true
+		}
+		_, _ = w.Write(util.EscapeHTML(util.StringToReadOnlyBytes(c)))
+	}
+	writeClass(class)
+	for _, c := range classes {
+		writeClass(c)
+	}
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name, "class") {
+			writeClass(attr.Value)
+		}
+	}
+	if hasClass {
+		_ = w.WriteByte('"')
 	}
 
-	r.hasDest.Store(n, struct{}{})
-	_, _ = w.WriteString(`<a `)
-	for _, attr := range r.Attributes {
+	for _, attr := range attrs {
+		if strings.EqualFold(attr.Name, "class") {
+			continue
+		}
+		_ = w.WriteByte(' ')
 		_, _ = w.WriteString(attr.Name)
 		_, _ = w.WriteString(`="`)
-		_, _ = w.Write(util.EscapeHTML([]byte(attr.Value)))
-		_, _ = w.WriteString(`" `)
+		_, _ = w.Write(util.EscapeHTML(util.StringToReadOnlyBytes(attr.Value)))
+		_ = w.WriteByte('"')
 	}
-	_, _ = w.WriteString(`href="`)
-	_, _ = w.Write(util.URLEscape(dest, true /* resolve references */))
-	_, _ = w.WriteString(`">`)
-	return nil
 }
 
-func (r *Renderer) exit(w util.BufWriter, n *Node) {
-	if _, ok := r.hasDest.LoadAndDelete(n); ok {
-		_, _ = w.WriteString("</a>")
+// nodeAttributes returns the attributes set on the given node
+// that are allowed by AttributeFilter.
+func nodeAttributes(n *Node) []Attribute {
+	var attrs []Attribute
+	for _, attr := range n.Attributes() {
+		if !AttributeFilter.Contains(attr.Name) && !bytes.HasPrefix(attr.Name, []byte("data-")) {
+			continue
+		}
+		if !isAttributeName(string(attr.Name)) {
+			continue
+		}
+
+		var value string
+		switch v := attr.Value.(type) {
+		case []byte:
+			value = string(v)
+		case string:
+			value = v
+		default:
+			value = fmt.Sprint(v)
+		}
+		attrs = append(attrs, Attribute{Name: string(attr.Name), Value: value})
 	}
-	_, _ = w.WriteString("</span>")
+	return attrs
 }
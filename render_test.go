@@ -4,13 +4,17 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
@@ -113,6 +117,79 @@ func TestRender_ResolveError(t *testing.T) {
 	assert.ErrorIs(t, err, giveErr)
 }
 
+func TestRenderer_OnResolveError(t *testing.T) {
+	t.Parallel()
+
+	giveErr := errors.New("great sadness")
+
+	tests := []struct {
+		desc    string
+		policy  ResolveErrorPolicy
+		class   string
+		want    string
+		wantErr bool
+	}{
+		{
+			desc:    "abort",
+			policy:  AbortOnResolveError,
+			wantErr: true,
+		},
+		{
+			desc:   "plain text",
+			policy: PlainTextOnResolveError,
+			want:   "<p>foo <span class=\"hashtag\">#bar</span>\n<span class=\"hashtag\">#baz</span></p>\n",
+		},
+		{
+			desc:   "error class",
+			policy: ErrorClassOnResolveError,
+			want:   "<p>foo <span class=\"hashtag hashtag-error\">#bar</span>\n<span class=\"hashtag hashtag-error\">#baz</span></p>\n",
+		},
+		{
+			desc:   "custom error class",
+			policy: ErrorClassOnResolveError,
+			class:  "broken",
+			want:   "<p>foo <span class=\"hashtag broken\">#bar</span>\n<span class=\"hashtag broken\">#baz</span></p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(goldmark.WithExtensions(&Extender{
+				Resolver:       constResolver{Err: giveErr},
+				OnResolveError: tt.policy,
+				ErrorClass:     tt.class,
+			}))
+
+			pc := parser.NewContext()
+			var buff bytes.Buffer
+			err := md.Convert([]byte("foo #bar\n#baz"), &buff, parser.WithContext(pc))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, giveErr)
+				assert.ErrorContains(t, err, `1:5: resolve hashtag "bar"`)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, buff.String())
+			}
+
+			errs := ResolveErrors(pc)
+			if tt.wantErr {
+				require.Len(t, errs, 1)
+			} else {
+				require.Len(t, errs, 2)
+				assert.Equal(t, "baz", string(errs[1].Tag))
+				assert.Equal(t, Position{Offset: 9, Line: 2, Column: 1}, errs[1].Position)
+			}
+			assert.Equal(t, "bar", string(errs[0].Tag))
+			assert.Equal(t, Position{Offset: 4, Line: 1, Column: 5}, errs[0].Position)
+			assert.ErrorIs(t, errs[0], giveErr)
+		})
+	}
+}
+
 func TestRenderer_Attributes(t *testing.T) {
 	t.Parallel()
 
@@ -178,6 +255,412 @@ func TestRenderer_Attributes(t *testing.T) {
 	}
 }
 
+func TestRenderer_ContextResolver(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		Resolver: prefixResolver{},
+	}))
+
+	tests := []struct {
+		desc   string
+		prefix string
+		want   string
+	}{
+		{
+			desc: "no prefix",
+			want: `<p><span class="hashtag"><a href="/tags/foo">#foo</a></span></p>` + "\n",
+		},
+		{
+			desc:   "prefix",
+			prefix: "../..",
+			want:   `<p><span class="hashtag"><a href="../../tags/foo">#foo</a></span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pc := parser.NewContext()
+			if tt.prefix != "" {
+				pc.Set(_prefixKey, tt.prefix)
+			}
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestRenderer_ContextResolverWithoutContext(t *testing.T) {
+	t.Parallel()
+
+	r := goldmark.New().Renderer()
+	r.AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&Renderer{
+				Resolver: prefixResolver{},
+			}, 999),
+		),
+	)
+
+	src := []byte("#foo")
+	node := &Node{Tag: src[1:]}
+	node.AppendChild(node,
+		ast.NewTextSegment(text.NewSegment(0, len(src))))
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Render(&buff, src, node))
+	assert.Equal(t, `<span class="hashtag"><a href="/tags/foo">#foo</a></span>`, buff.String())
+}
+
+func TestRenderer_DetailedResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give Resolution
+		want string
+	}{
+		{
+			desc: "no destination",
+			want: `<span class="hashtag">#foo</span>`,
+		},
+		{
+			desc: "classes without destination",
+			give: Resolution{Classes: []string{"hashtag-missing"}},
+			want: `<span class="hashtag hashtag-missing">#foo</span>`,
+		},
+		{
+			desc: "attributes without destination",
+			give: Resolution{Attributes: []Attribute{{Name: "title", Value: "Foo"}}},
+			want: `<span class="hashtag">#foo</span>`,
+		},
+		{
+			desc: "destination",
+			give: Resolution{Destination: []byte("/foo")},
+			want: `<span class="hashtag"><a class="p-category" href="/foo">#foo</a></span>`,
+		},
+		{
+			desc: "attributes",
+			give: Resolution{
+				Destination: []byte("/foo"),
+				Attributes: []Attribute{
+					{Name: "title", Value: `Things about "foo"`},
+					{Name: "rel", Value: "tag"},
+					{Name: "data-count", Value: "42"},
+				},
+				Classes: []string{"popular"},
+			},
+			want: `<span class="hashtag popular">` +
+				`<a class="p-category" title="Things about &quot;foo&quot;" rel="tag" data-count="42" href="/foo">` +
+				`#foo</a></span>`,
+		},
+		{
+			desc: "merge class",
+			give: Resolution{
+				Destination: []byte("/foo"),
+				Attributes:  []Attribute{{Name: "class", Value: "popular"}},
+			},
+			want: `<span class="hashtag"><a class="p-category popular" href="/foo">#foo</a></span>`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			r := goldmark.New().Renderer()
+			r.AddOptions(
+				renderer.WithNodeRenderers(
+					util.Prioritized(&Renderer{
+						Resolver:   detailedResolver{Resolution: tt.give},
+						Attributes: []Attribute{{Name: "class", Value: "p-category"}},
+					}, 999),
+				),
+			)
+
+			src := []byte("#foo")
+			node := &Node{Tag: src[1:]}
+			node.AppendChild(node,
+				ast.NewTextSegment(text.NewSegment(0, len(src))))
+
+			var buff bytes.Buffer
+			require.NoError(t, r.Render(&buff, src, node))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestRenderer_Elements(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		renderer *Renderer
+		dest     string
+		want     string
+	}{
+		{
+			desc:     "element",
+			renderer: &Renderer{Element: "mark"},
+			want:     `<mark class="hashtag">#foo</mark>`,
+		},
+		{
+			desc:     "element with destination",
+			renderer: &Renderer{Element: "mark"},
+			dest:     "/foo",
+			want:     `<mark class="hashtag"><a href="/foo">#foo</a></mark>`,
+		},
+		{
+			desc:     "class",
+			renderer: &Renderer{Element: "mark", Class: "tag__label"},
+			want:     `<mark class="tag__label">#foo</mark>`,
+		},
+		{
+			desc:     "link class",
+			renderer: &Renderer{LinkClass: "chip tag"},
+			dest:     "/foo",
+			want:     `<span class="hashtag"><a class="chip tag" href="/foo">#foo</a></span>`,
+		},
+		{
+			desc: "link class with attributes",
+			renderer: &Renderer{
+				LinkClass:  "chip",
+				Attributes: []Attribute{{Name: "class", Value: "p-category"}},
+			},
+			dest: "/foo",
+			want: `<span class="hashtag"><a class="chip p-category" href="/foo">#foo</a></span>`,
+		},
+		{
+			desc:     "no wrapper",
+			renderer: &Renderer{NoWrapper: true, LinkClass: "chip tag"},
+			dest:     "/foo",
+			want:     `<a class="chip tag" href="/foo">#foo</a>`,
+		},
+		{
+			desc:     "no wrapper without destination",
+			renderer: &Renderer{NoWrapper: true, LinkClass: "chip tag"},
+			want:     `#foo`,
+		},
+		{
+			desc: "display",
+			renderer: &Renderer{
+				Display: func(n *Node) string { return "<" + string(n.Tag) + ">" },
+			},
+			dest: "/foo",
+			want: `<span class="hashtag"><a href="/foo">&lt;foo&gt;</a></span>`,
+		},
+		{
+			desc:     "rdfa",
+			renderer: &Renderer{Markup: RDFaMarkup},
+			dest:     "/foo",
+			want:     `<span class="hashtag" property="keywords"><a href="/foo">#foo</a></span>`,
+		},
+		{
+			desc:     "microformats without wrapper",
+			renderer: &Renderer{Markup: MicroformatsMarkup | MicrodataMarkup, NoWrapper: true},
+			dest:     "/foo",
+			want:     `<a class="p-category" rel="tag" href="/foo">#foo</a>`,
+		},
+		{
+			desc: "wrapper attributes",
+			renderer: &Renderer{
+				WrapperAttributes: []Attribute{
+					{Name: "class", Value: "tag"},
+					{Name: "role", Value: "listitem"},
+				},
+			},
+			want: `<span class="hashtag tag" role="listitem">#foo</span>`,
+		},
+		{
+			desc: "no wrapper attributes",
+			renderer: &Renderer{
+				NoWrapper:         true,
+				WrapperAttributes: []Attribute{{Name: "role", Value: "listitem"}},
+			},
+			dest: "/foo",
+			want: `<a role="listitem" href="/foo">#foo</a>`,
+		},
+		{
+			desc: "no wrapper missing",
+			renderer: &Renderer{
+				NoWrapper: true,
+				Resolver: detailedResolver{
+					Resolution: Resolution{
+						Destination: []byte("/new"),
+						Missing:     true,
+					},
+				},
+			},
+			want: `<a class="hashtag-missing" href="/new">#foo</a>`,
+		},
+		{
+			desc: "no wrapper missing without destination",
+			renderer: &Renderer{
+				NoWrapper: true,
+				Resolver: detailedResolver{
+					Resolution: Resolution{Missing: true},
+				},
+			},
+			want: `<span class="hashtag-missing">#foo</span>`,
+		},
+		{
+			desc: "no wrapper error class",
+			renderer: &Renderer{
+				NoWrapper:      true,
+				Resolver:       constResolver{Err: errors.New("great sadness")},
+				OnResolveError: ErrorClassOnResolveError,
+			},
+			want: `<span class="hashtag-error">#foo</span>`,
+		},
+		{
+			desc: "no wrapper attributes without destination",
+			renderer: &Renderer{
+				NoWrapper:         true,
+				Element:           "mark",
+				WrapperAttributes: []Attribute{{Name: "role", Value: "listitem"}},
+			},
+			want: `<mark role="listitem">#foo</mark>`,
+		},
+		{
+			desc: "no wrapper style without destination",
+			renderer: &Renderer{
+				NoWrapper: true,
+				Style:     &Style{Colors: map[string]string{"foo": "red"}},
+			},
+			want: `<span style="--hashtag-color: red">#foo</span>`,
+		},
+		{
+			desc:     "microformats without wrapper or destination",
+			renderer: &Renderer{Markup: MicroformatsMarkup, NoWrapper: true},
+			want:     `<span class="p-category">#foo</span>`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rend := tt.renderer
+			if rend.Resolver == nil {
+				rend.Resolver = constResolver{Dest: tt.dest}
+			}
+
+			r := goldmark.New().Renderer()
+			r.AddOptions(
+				renderer.WithNodeRenderers(
+					util.Prioritized(rend, 999),
+				),
+			)
+
+			src := []byte("#foo")
+			node := &Node{Tag: src[1:]}
+			node.AppendChild(node,
+				ast.NewTextSegment(text.NewSegment(0, len(src))))
+
+			var buff bytes.Buffer
+			require.NoError(t, r.Render(&buff, src, node))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestRenderer_HTMLOptions(t *testing.T) {
+	t.Parallel()
+
+	r := &Renderer{}
+	md := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+			html.WithXHTML(),
+			renderer.WithNodeRenderers(util.Prioritized(r, 999)),
+		),
+	)
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#foo"), &buff))
+	assert.True(t, r.Unsafe, "Unsafe")
+	assert.True(t, r.XHTML, "XHTML")
+}
+
+func TestRenderer_NodeAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		renderer *Renderer
+		attrs    []ast.Attribute
+		want     string
+	}{
+		{
+			desc: "attributes",
+			attrs: []ast.Attribute{
+				{Name: []byte("id"), Value: "foo"},
+				{Name: []byte("title"), Value: []byte(`"foo"`)},
+			},
+			want: `<span class="hashtag" id="foo" title="&quot;foo&quot;">#foo</span>`,
+		},
+		{
+			desc:  "class",
+			attrs: []ast.Attribute{{Name: []byte("class"), Value: "important"}},
+			want:  `<span class="hashtag important">#foo</span>`,
+		},
+		{
+			desc:  "data attributes",
+			attrs: []ast.Attribute{{Name: []byte("data-count"), Value: 42}},
+			want:  `<span class="hashtag" data-count="42">#foo</span>`,
+		},
+		{
+			desc:  "filtered",
+			attrs: []ast.Attribute{{Name: []byte("onclick"), Value: "alert(1)"}},
+			want:  `<span class="hashtag">#foo</span>`,
+		},
+		{
+			desc:     "no wrapper",
+			renderer: &Renderer{NoWrapper: true, Resolver: constResolver{Dest: "/foo"}},
+			attrs:    []ast.Attribute{{Name: []byte("id"), Value: "foo"}},
+			want:     `<a id="foo" href="/foo">#foo</a>`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rend := tt.renderer
+			if rend == nil {
+				rend = &Renderer{}
+			}
+
+			r := goldmark.New().Renderer()
+			r.AddOptions(
+				renderer.WithNodeRenderers(
+					util.Prioritized(rend, 999),
+				),
+			)
+
+			src := []byte("#foo")
+			node := &Node{Tag: src[1:]}
+			node.AppendChild(node,
+				ast.NewTextSegment(text.NewSegment(0, len(src))))
+			for _, attr := range tt.attrs {
+				node.SetAttribute(attr.Name, attr.Value)
+			}
+
+			var buff bytes.Buffer
+			require.NoError(t, r.Render(&buff, src, node))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
 type constResolver struct {
 	Dest string
 	Err  error
@@ -186,3 +669,79 @@ type constResolver struct {
 func (r constResolver) ResolveHashtag(*Node) ([]byte, error) {
 	return []byte(r.Dest), r.Err
 }
+
+var _prefixKey = parser.NewContextKey()
+
+// Resolves tags to "/tags/<tag>",
+// prefixed with the value of _prefixKey in the parser.Context, if any.
+type prefixResolver struct{}
+
+var _ ContextResolver = prefixResolver{}
+
+func (prefixResolver) ResolveHashtag(n *Node) ([]byte, error) {
+	return prefixResolver{}.ResolveHashtagContext(parser.NewContext(), n)
+}
+
+func (prefixResolver) ResolveHashtagContext(pc parser.Context, n *Node) ([]byte, error) {
+	prefix, _ := pc.Get(_prefixKey).(string)
+	return []byte(prefix + "/tags/" + string(n.Tag)), nil
+}
+
+type detailedResolver struct {
+	Resolution Resolution
+	Err        error
+}
+
+var _ DetailedResolver = detailedResolver{}
+
+func (r detailedResolver) ResolveHashtag(*Node) ([]byte, error) {
+	return r.Resolution.Destination, r.Err
+}
+
+func (r detailedResolver) ResolveHashtagDetails(parser.Context, *Node) (Resolution, error) {
+	return r.Resolution, r.Err
+}
+
+func BenchmarkRenderer(b *testing.B) {
+	src := []byte(strings.Repeat("Lorem #ipsum dolor sit #amet, #consectetur adipiscing elit.\n\n", 100))
+
+	benchmarks := []struct {
+		name     string
+		resolver Resolver
+	}{
+		{name: "no resolver"},
+		{name: "resolver", resolver: constResolver{Dest: "/tag"}},
+	}
+
+	for _, bb := range benchmarks {
+		b.Run(bb.name, func(b *testing.B) {
+			md := goldmark.New(goldmark.WithExtensions(&Extender{
+				Resolver: bb.resolver,
+			}))
+
+			b.Run("render", func(b *testing.B) {
+				doc := md.Parser().Parse(text.NewReader(src))
+
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						if err := md.Renderer().Render(io.Discard, src, doc); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			})
+
+			b.Run("convert", func(b *testing.B) {
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						if err := md.Convert(src, io.Discard); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package hashtag
+
+import (
+	"sync"
+
+	"github.com/yuin/goldmark/parser"
+)
+
+var _reportKey = parser.NewContextKey()
+
+// report collects information about hashtags
+// as a document is rendered.
+//
+// The report is stored in the parser.Context when the document is parsed,
+// so the Renderer only reads the context.
+// This keeps rendering a parsed document safe for concurrent use.
+type report struct {
+	mu sync.Mutex
+
+	errors     []*ResolveError
+	errorIndex map[*Node]int // node => index in errors
+
+	tags     []Tag
+	tagIndex map[string]int // canonical name => index in tags
+}
+
+// reportFor returns the report for the document parsed with pc,
+// creating it if necessary.
+//
+// This must only be called while parsing.
+func reportFor(pc parser.Context) *report {
+	return pc.ComputeIfAbsent(_reportKey, func() any {
+		return new(report)
+	}).(*report)
+}
+
+// getReport returns the report for the document parsed with pc, if any.
+func getReport(pc parser.Context) *report {
+	r, _ := pc.Get(_reportKey).(*report)
+	return r
+}
+
+// addError records a ResolveError for the given hashtag.
+//
+// Rendering a document more than once
+// reports each hashtag's error only once.
+func (r *report) addError(n *Node, err *ResolveError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.errorIndex[n]; ok {
+		r.errors[i] = err
+		return
+	}
+
+	if r.errorIndex == nil {
+		r.errorIndex = make(map[*Node]int)
+	}
+	r.errorIndex[n] = len(r.errors)
+	r.errors = append(r.errors, err)
+}
+
+// resolveErrors returns a copy of the errors in the report.
+func (r *report) resolveErrors() []*ResolveError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return append([]*ResolveError(nil), r.errors...)
+}
+
+// addTag records a rendered hashtag.
+func (r *report) addTag(n *Node, dest []byte) {
+	name := Canonical(string(n.Tag))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.tagIndex[name]; ok {
+		// Keep the first destination,
+		// but fill it in if earlier occurrences weren't linked.
+		if len(r.tags[i].Destination) == 0 {
+			r.tags[i].Destination = dest
+		}
+		return
+	}
+
+	if r.tagIndex == nil {
+		r.tagIndex = make(map[string]int)
+	}
+	r.tagIndex[name] = len(r.tags)
+	r.tags = append(r.tags, Tag{
+		Name:        name,
+		Destination: dest,
+		Source:      InlineTag,
+	})
+}
+
+// collectedTags returns a copy of the tags in the report.
+func (r *report) collectedTags() []Tag {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.tags) == 0 {
+		return nil
+	}
+	return append([]Tag(nil), r.tags...)
+}
@@ -0,0 +1,119 @@
+package hashtag
+
+import "github.com/yuin/goldmark/parser"
+
+// Resolver resolves hashtags to pages they should link to.
+type Resolver interface {
+	// ResolveHashtag reports the link that the provided hashtag Node
+	// should point to, or an empty destination for hashtags that should
+	// not link to anything.
+	ResolveHashtag(*Node) (destination []byte, err error)
+}
+
+// ContextResolver is a Resolver that also has access to the parser.Context
+// of the document that the hashtag was found in.
+//
+// Use this to resolve hashtags relative to document-level information
+// such as the path of the current page, the base URL of the site,
+// or the document's frontmatter.
+// Attach this information to a parser.Context and pass it to the conversion.
+//
+//	pc := parser.NewContext()
+//	pc.Set(pageKey, page)
+//	md.Convert(src, &buf, parser.WithContext(pc))
+//
+// The Renderer will call ResolveHashtagContext instead of ResolveHashtag
+// if the Resolver implements this interface.
+type ContextResolver interface {
+	Resolver
+
+	// ResolveHashtagContext reports the link that the provided hashtag
+	// Node should point to, or an empty destination for hashtags that
+	// should not link to anything.
+	//
+	// pc is the parser.Context that the document was parsed with.
+	// It is empty for nodes that were not created by the Parser.
+	ResolveHashtagContext(pc parser.Context, n *Node) (destination []byte, err error)
+}
+
+// DetailedResolver is a Resolver that reports more than the destination
+// of a hashtag.
+//
+// Use this to add per-tag attributes to hashtag links,
+// (e.g. a title with the description of the tag)
+// or per-tag classes to hashtags.
+//
+// The Renderer will call ResolveHashtagDetails instead of ResolveHashtag
+// or ResolveHashtagContext if the Resolver implements this interface.
+type DetailedResolver interface {
+	Resolver
+
+	// ResolveHashtagDetails reports how the provided hashtag Node
+	// should be rendered.
+	//
+	// pc is the parser.Context that the document was parsed with.
+	// It is empty for nodes that were not created by the Parser.
+	ResolveHashtagDetails(pc parser.Context, n *Node) (Resolution, error)
+}
+
+// Resolution is the result of resolving a hashtag with a DetailedResolver.
+//
+// A Resolution describes one of the following states:
+//
+//   - linked: Destination is non-empty and Missing is false.
+//     The hashtag links to its page.
+//   - unlinked: Destination is empty and Missing is false.
+//     The hashtag is not meant to link anywhere.
+//   - missing: Missing is true.
+//     The hashtag should have a page but it does not exist yet.
+//     The hashtag is rendered with the Renderer's MissingClass,
+//     and links to Destination if it's non-empty
+//     (e.g. a page to create the missing page).
+type Resolution struct {
+	// Destination is the link that the hashtag should point to,
+	// or empty if the hashtag should not link to anything.
+	Destination []byte
+
+	// Missing reports that the page for this hashtag does not exist.
+	Missing bool
+
+	// Attributes are added to the <a> tag.
+	//
+	// These are merged with the Renderer's Attributes:
+	// an attribute here replaces a Renderer attribute with the same name,
+	// except "class" attributes, which are combined.
+	//
+	// Attributes will only be applied if Destination is non-empty.
+	Attributes []Attribute
+
+	// Classes are added to the class attribute
+	// of the element wrapping the hashtag.
+	Classes []string
+}
+
+// resolve resolves a hashtag with the given Resolver,
+// using the most detailed interface that it implements.
+func resolve(res Resolver, n *Node) (Resolution, error) {
+	if res == nil {
+		return Resolution{}, nil
+	}
+
+	pc := n.context
+	if pc == nil {
+		pc = parser.NewContext()
+	}
+
+	var (
+		dest []byte
+		err  error
+	)
+	switch res := res.(type) {
+	case DetailedResolver:
+		return res.ResolveHashtagDetails(pc, n)
+	case ContextResolver:
+		dest, err = res.ResolveHashtagContext(pc, n)
+	default:
+		dest, err = res.ResolveHashtag(n)
+	}
+	return Resolution{Destination: dest}, err
+}
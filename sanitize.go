@@ -0,0 +1,75 @@
+package hashtag
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// _defaultAllowedSchemes are the URL schemes allowed in destinations
+// if Renderer.AllowedSchemes is not set.
+var _defaultAllowedSchemes = []string{"http", "https"}
+
+// isAllowedDestination reports whether the given destination
+// is a relative URL or uses one of the allowed schemes,
+// and isn't considered dangerous by goldmark.
+func isAllowedDestination(dest []byte, allowed []string) bool {
+	if html.IsDangerousURL(dest) {
+		return false
+	}
+
+	scheme, ok := urlScheme(dest)
+	if !ok {
+		return true // relative URL
+	}
+
+	if len(allowed) == 0 {
+		allowed = _defaultAllowedSchemes
+	}
+	for _, s := range allowed {
+		if strings.EqualFold(s, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlScheme returns the scheme of the given URL, if it has one.
+//
+// Browsers ignore leading spaces and control characters,
+// and ASCII tabs and newlines anywhere in a URL,
+// so these are ignored when looking for the scheme.
+func urlScheme(url []byte) (scheme string, ok bool) {
+	url = bytes.TrimLeftFunc(url, func(r rune) bool { return r <= ' ' })
+
+	var b strings.Builder
+	for _, c := range url {
+		switch {
+		case c == '\t' || c == '\n' || c == '\r':
+			// ignored
+		case c == ':':
+			return b.String(), b.Len() > 0
+		case isSchemeChar(c, b.Len() == 0):
+			_ = b.WriteByte(c)
+		default:
+			// Anything else (e.g. '/', '?', '#') before the ':'
+			// means that this is a relative URL.
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// isSchemeChar reports whether c is valid in a URL scheme
+// per RFC 3986.
+func isSchemeChar(c byte, first bool) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9', c == '+', c == '-', c == '.':
+		return !first
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+func TestIsAllowedDestination(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		give    string
+		allowed []string
+		want    bool
+	}{
+		{give: "/tags/foo", want: true},
+		{give: "tags/foo", want: true},
+		{give: "../foo", want: true},
+		{give: "?tag=foo", want: true},
+		{give: "#foo", want: true},
+		{give: "foo/bar:baz", want: true},
+		{give: "http://example.com/foo", want: true},
+		{give: "HTTPS://example.com/foo", want: true},
+		{give: "//example.com/foo", want: true},
+		{give: "javascript:alert(1)", want: false},
+		{give: "JavaScript:alert(1)", want: false},
+		{give: " javascript:alert(1)", want: false},
+		{give: "java\tscript:alert(1)", want: false},
+		{give: "java\nscript:alert(1)", want: false},
+		{give: "vbscript:foo", want: false},
+		{give: "data:text/html,foo", want: false},
+		{give: "file:///etc/passwd", want: false},
+		{give: "mailto:foo@example.com", want: false},
+		{give: "mailto:foo@example.com", allowed: []string{"mailto"}, want: true},
+		{give: "http://example.com", allowed: []string{"mailto"}, want: false},
+		{give: "javascript:alert(1)", allowed: []string{"javascript"}, want: false},
+		{give: "1http://example.com", want: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.give, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, isAllowedDestination([]byte(tt.give), tt.allowed))
+		})
+	}
+}
+
+func TestRenderer_UnsafeDestination(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc   string
+		unsafe bool
+		want   string
+	}{
+		{
+			desc: "safe",
+			want: `<p><span class="hashtag">#foo</span></p>` + "\n",
+		},
+		{
+			desc:   "unsafe",
+			unsafe: true,
+			want:   `<p><span class="hashtag"><a href="javascript:alert(1)">#foo</a></span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []goldmark.Option
+			if tt.unsafe {
+				opts = append(opts, goldmark.WithRendererOptions(html.WithUnsafe()))
+			}
+			opts = append(opts, goldmark.WithExtensions(&Extender{
+				Resolver: constResolver{Dest: "javascript:alert(1)"},
+			}))
+			md := goldmark.New(opts...)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte("#foo"), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package hashtag
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// Style assigns colours and icons to hashtags
+// so that the same hashtag looks the same on every page.
+//
+//	&hashtag.Style{
+//		Colors: map[string]string{
+//			"urgent": "red",
+//		},
+//		HashColors: true,
+//		Icons: map[string]string{
+//			"urgent": "🔥",
+//		},
+//	}
+//
+// By default, the Renderer sets the colour of a hashtag
+// as a CSS custom property on its element.
+//
+//	<span class="hashtag" style="--hashtag-color: red">🔥#urgent</span>
+//
+// Use it in a stylesheet to style hashtags.
+//
+//	.hashtag { color: var(--hashtag-color, inherit); }
+type Style struct {
+	// Colors maps canonical hashtags to their colours.
+	// See Canonical for the canonical form of a hashtag.
+	//
+	// Colours are CSS colour values, or class name suffixes if Classes is set.
+	Colors map[string]string
+
+	// HashColors specifies that hashtags not found in Colors
+	// get a colour derived from a hash of the hashtag.
+	//
+	// The colour is an HSL colour with a stable hue,
+	// or a class name suffix "hue-N" where N is the hue
+	// rounded down to a multiple of 30 if Classes is set.
+	HashColors bool
+
+	// Property is the name of the CSS custom property
+	// set to the colour of the hashtag.
+	//
+	// Defaults to "--hashtag-color".
+	Property string
+
+	// Classes specifies that colours are added as classes
+	// named ClassPrefix followed by the colour,
+	// instead of setting a CSS custom property.
+	Classes bool
+
+	// ClassPrefix is the prefix for colour classes if Classes is set.
+	//
+	// Defaults to "hashtag-".
+	ClassPrefix string
+
+	// Icons maps canonical hashtags to text that is displayed
+	// before the hashtag (e.g. an emoji).
+	Icons map[string]string
+}
+
+// Color reports the colour of the given hashtag,
+// or an empty string if it doesn't have one.
+func (s *Style) Color(tag string) string {
+	if s == nil {
+		return ""
+	}
+
+	tag = Canonical(tag)
+	if c, ok := s.Colors[tag]; ok {
+		return c
+	}
+	if !s.HashColors {
+		return ""
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tag))
+	hue := h.Sum32() % 360
+	if s.Classes {
+		return "hue-" + strconv.Itoa(int(hue/30*30))
+	}
+	return "hsl(" + strconv.Itoa(int(hue)) + ", 65%, 45%)"
+}
+
+// Icon reports the icon for the given hashtag,
+// or an empty string if it doesn't have one.
+func (s *Style) Icon(tag string) string {
+	if s == nil {
+		return ""
+	}
+	return s.Icons[Canonical(tag)]
+}
+
+// attributes returns the attributes that style the given hashtag.
+func (s *Style) attributes(tag string) []Attribute {
+	color := s.Color(tag)
+	if color == "" {
+		return nil
+	}
+
+	if s.Classes {
+		prefix := s.ClassPrefix
+		if prefix == "" {
+			prefix = "hashtag-"
+		}
+		return []Attribute{{Name: "class", Value: prefix + color}}
+	}
+
+	prop := s.Property
+	if prop == "" {
+		prop = "--hashtag-color"
+	}
+	return []Attribute{{Name: "style", Value: prop + ": " + color}}
+}
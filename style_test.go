@@ -0,0 +1,154 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestStyle_Color(t *testing.T) {
+	t.Parallel()
+
+	style := &Style{
+		Colors: map[string]string{"urgent": "red"},
+	}
+	assert.Equal(t, "red", style.Color("urgent"))
+	assert.Equal(t, "red", style.Color("#Urgent"))
+	assert.Empty(t, style.Color("other"))
+
+	t.Run("hash", func(t *testing.T) {
+		t.Parallel()
+
+		style := &Style{
+			Colors:     map[string]string{"urgent": "red"},
+			HashColors: true,
+		}
+		assert.Equal(t, "red", style.Color("urgent"))
+
+		got := style.Color("other")
+		assert.Regexp(t, `^hsl\(\d+, 65%, 45%\)$`, got)
+		assert.Equal(t, got, style.Color("Other"), "must be stable")
+		assert.NotEqual(t, got, style.Color("another"))
+	})
+
+	t.Run("hash classes", func(t *testing.T) {
+		t.Parallel()
+
+		style := &Style{HashColors: true, Classes: true}
+		got := style.Color("other")
+		assert.Regexp(t, `^hue-\d*0$`, got)
+		assert.Equal(t, got, style.Color("other"), "must be stable")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		var style *Style
+		assert.Empty(t, style.Color("urgent"))
+		assert.Empty(t, style.Icon("urgent"))
+	})
+}
+
+func TestStyle_Icon(t *testing.T) {
+	t.Parallel()
+
+	style := &Style{Icons: map[string]string{"idea": "💡"}}
+	assert.Equal(t, "💡", style.Icon("Idea"))
+	assert.Empty(t, style.Icon("other"))
+}
+
+func TestRenderer_Style(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		renderer *Renderer
+		give     string
+		want     string
+	}{
+		{
+			desc: "property",
+			renderer: &Renderer{
+				Style: &Style{
+					Colors: map[string]string{"urgent": "#f00"},
+					Icons:  map[string]string{"urgent": "<!>"},
+				},
+			},
+			give: "#Urgent #other",
+			want: `<p><span class="hashtag" style="--hashtag-color: #f00">&lt;!&gt;#Urgent</span>` +
+				` <span class="hashtag">#other</span></p>` + "\n",
+		},
+		{
+			desc: "custom property",
+			renderer: &Renderer{
+				Style: &Style{
+					Colors:   map[string]string{"urgent": "red"},
+					Property: "--tag",
+				},
+			},
+			give: "#urgent",
+			want: `<p><span class="hashtag" style="--tag: red">#urgent</span></p>` + "\n",
+		},
+		{
+			desc: "classes",
+			renderer: &Renderer{
+				Style: &Style{
+					Colors:  map[string]string{"urgent": "red"},
+					Classes: true,
+				},
+			},
+			give: "#urgent",
+			want: `<p><span class="hashtag hashtag-red">#urgent</span></p>` + "\n",
+		},
+		{
+			desc: "class prefix",
+			renderer: &Renderer{
+				Style: &Style{
+					Colors:      map[string]string{"urgent": "red"},
+					Classes:     true,
+					ClassPrefix: "tag--",
+				},
+			},
+			give: "#urgent",
+			want: `<p><span class="hashtag tag--red">#urgent</span></p>` + "\n",
+		},
+		{
+			desc: "no wrapper",
+			renderer: &Renderer{
+				NoWrapper: true,
+				Resolver:  constResolver{Dest: "/urgent"},
+				Style: &Style{
+					Colors: map[string]string{"urgent": "red"},
+					Icons:  map[string]string{"urgent": "🔥"},
+				},
+			},
+			give: "#urgent",
+			want: `<p><a style="--hashtag-color: red" href="/urgent">🔥#urgent</a></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New()
+			md.Renderer().AddOptions(
+				renderer.WithNodeRenderers(util.Prioritized(tt.renderer, 999)),
+			)
+			md.Parser().AddOptions(
+				parser.WithInlineParsers(util.Prioritized(&Parser{}, 999)),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
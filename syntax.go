@@ -0,0 +1,278 @@
+package hashtag
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Syntax is the hashtag syntax used for a single document.
+//
+// Documents choose their variant with a "hashtag" key in their frontmatter
+// (see Parser.Frontmatter)
+//
+//	---
+//	hashtag: obsidian
+//	---
+//
+// or with an HTML comment directive at the top level of the document.
+//
+//	<!-- hashtag: obsidian -->
+//
+// The value is the name of a variant: "default" or "obsidian".
+// In frontmatter, it may also be "off"
+// to disable hashtags in the entire document.
+// If both are present, the frontmatter takes precedence,
+// and only the first variant directive in the document is used.
+// Unrecognized values are ignored.
+//
+// Directives also disable hashtags in parts of a document.
+// Hashtags are disabled after an "off" directive
+// until the next "on" directive or the end of the document,
+//
+//	<!-- hashtag: off -->
+//	#define FOO 1
+//	<!-- hashtag: on -->
+//
+// and in the block immediately following a "skip" directive.
+//
+//	<!-- hashtag: skip -->
+//	- Fixed #123.
+//	- Fixed #124.
+//
+// Unlike variant directives, these take effect where they appear,
+// at any level of the document, including inside lists and block quotes.
+// Hashtags before an "off" directive are not affected,
+// so an "off" directive at the top of a document disables all its hashtags.
+//
+// Directives must be written as HTML blocks on their own lines.
+//
+// Use SetSyntax to choose the syntax for a document programmatically.
+type Syntax struct {
+	// Variant is the flavor of the hashtag syntax used in the document.
+	Variant Variant
+
+	// Disabled specifies that hashtags are not parsed in the document.
+	Disabled bool
+}
+
+// SyntaxKey is the frontmatter key and directive name
+// that choose the hashtag syntax for a document.
+const SyntaxKey = "hashtag"
+
+var (
+	_syntaxKey         = parser.NewContextKey()
+	_documentSyntaxKey = parser.NewContextKey()
+)
+
+// SetSyntax sets the hashtag syntax for the document parsed with pc.
+// This takes precedence over the Parser's Variant,
+// frontmatter, and directives in the document.
+//
+//	pc := parser.NewContext()
+//	hashtag.SetSyntax(pc, hashtag.Syntax{Variant: hashtag.ObsidianVariant})
+//	md.Convert(src, &buf, parser.WithContext(pc))
+func SetSyntax(pc parser.Context, s Syntax) {
+	pc.Set(_syntaxKey, s)
+}
+
+// documentSyntax is the syntax chosen by a document
+// and the regions of it where hashtags are disabled,
+// cached in the parser.Context.
+type documentSyntax struct {
+	doc    *ast.Document
+	syntax Syntax
+
+	// regions are the offsets of "on" and "off" directives
+	// in the document, in order.
+	regions []region
+
+	// skipped are the blocks that follow "skip" directives.
+	skipped map[ast.Node]struct{}
+}
+
+// region is a part of the document that starts at a directive
+// and continues until the next one.
+type region struct {
+	offset   int
+	disabled bool
+}
+
+// disabled reports whether hashtags are disabled
+// at the given offset inside the given node.
+func (d *documentSyntax) disabled(node ast.Node, offset int) bool {
+	if d.syntax.Disabled {
+		return true
+	}
+
+	// Index of the first directive after the offset.
+	i := sort.Search(len(d.regions), func(i int) bool {
+		return d.regions[i].offset > offset
+	})
+	if i > 0 && d.regions[i-1].disabled {
+		return true
+	}
+
+	if len(d.skipped) > 0 {
+		for n := node; n != nil; n = n.Parent() {
+			if _, ok := d.skipped[n]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// document returns the hashtag syntax for the document containing node.
+func (p *Parser) document(node ast.Node, block text.Reader, pc parser.Context) *documentSyntax {
+	var doc *ast.Document
+	if node != nil {
+		doc = node.OwnerDocument()
+	}
+
+	// Parse is called for every "#" in the document,
+	// so cache the result for the document.
+	if cached, ok := pc.Get(_documentSyntaxKey).(*documentSyntax); ok && cached.doc == doc {
+		return cached
+	}
+
+	d := &documentSyntax{
+		doc:    doc,
+		syntax: Syntax{Variant: p.Variant},
+	}
+	var directiveSyntax *Syntax
+	if doc != nil {
+		directiveSyntax = p.scanDirectives(d, doc, block.Source())
+	}
+
+	if s, ok := pc.Get(_syntaxKey).(Syntax); ok {
+		d.syntax = s
+	} else if fs, ok := p.frontmatterSyntax(pc); ok {
+		d.syntax = fs
+	} else if directiveSyntax != nil {
+		d.syntax = *directiveSyntax
+	}
+
+	pc.Set(_documentSyntaxKey, d)
+	return d
+}
+
+// frontmatterSyntax returns the syntax chosen by the document's frontmatter.
+func (p *Parser) frontmatterSyntax(pc parser.Context) (Syntax, bool) {
+	if p.Frontmatter == nil {
+		return Syntax{}, false
+	}
+	v, ok := p.Frontmatter(pc)[SyntaxKey]
+	if !ok {
+		return Syntax{}, false
+	}
+	return p.parseSyntax(v)
+}
+
+// scanDirectives records the regions and skipped blocks
+// of the hashtag directives in the document.
+// It returns the syntax chosen by the first variant directive
+// at the top level of the document, if any.
+func (p *Parser) scanDirectives(d *documentSyntax, doc *ast.Document, src []byte) (syntax *Syntax) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() == ast.TypeInline {
+			return ast.WalkSkipChildren, nil
+		}
+
+		b, ok := n.(*ast.HTMLBlock)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		value, ok := directive(htmlBlockText(b, src))
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		switch strings.ToLower(value) {
+		case "on", "off":
+			d.regions = append(d.regions, region{
+				offset:   b.Lines().At(0).Start,
+				disabled: strings.EqualFold(value, "off"),
+			})
+		case "skip":
+			if next := b.NextSibling(); next != nil {
+				if d.skipped == nil {
+					d.skipped = make(map[ast.Node]struct{})
+				}
+				d.skipped[next] = struct{}{}
+			}
+		default:
+			if syntax == nil && b.Parent() == doc {
+				if s, ok := p.parseSyntax(value); ok {
+					syntax = &s
+				}
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return syntax
+}
+
+// parseSyntax parses the value of a frontmatter key or directive.
+//
+// YAML 1.1 decoders report "on" and "off" as booleans,
+// so those are accepted as well.
+func (p *Parser) parseSyntax(v any) (Syntax, bool) {
+	switch v := v.(type) {
+	case bool:
+		return Syntax{Variant: p.Variant, Disabled: !v}, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "default":
+			return Syntax{Variant: DefaultVariant}, true
+		case "obsidian":
+			return Syntax{Variant: ObsidianVariant}, true
+		case "on":
+			return Syntax{Variant: p.Variant}, true
+		case "off":
+			return Syntax{Variant: p.Variant, Disabled: true}, true
+		}
+	}
+	return Syntax{}, false
+}
+
+// directive returns the value of a hashtag directive
+// in the given HTML comment.
+//
+//	<!-- hashtag: value -->
+func directive(html []byte) (string, bool) {
+	html = bytes.TrimSpace(html)
+	body, ok := bytes.CutPrefix(html, []byte("<!--"))
+	if !ok {
+		return "", false
+	}
+	body, ok = bytes.CutSuffix(body, []byte("-->"))
+	if !ok {
+		return "", false
+	}
+
+	name, value, ok := bytes.Cut(bytes.TrimSpace(body), []byte(":"))
+	if !ok || !bytes.EqualFold(bytes.TrimSpace(name), []byte(SyntaxKey)) {
+		return "", false
+	}
+	return string(bytes.TrimSpace(value)), true
+}
+
+// htmlBlockText returns the text of an HTML block,
+// including its closure line, if any.
+func htmlBlockText(b *ast.HTMLBlock, src []byte) []byte {
+	var buf bytes.Buffer
+	lines := b.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		buf.Write(seg.Value(src))
+	}
+	if b.HasClosure() {
+		buf.Write(b.ClosureLine.Value(src))
+	}
+	return buf.Bytes()
+}
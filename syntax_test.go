@@ -0,0 +1,267 @@
+package hashtag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+var _frontmatterKey = parser.NewContextKey()
+
+// Reads frontmatter from _frontmatterKey in the parser.Context.
+func contextFrontmatter(pc parser.Context) map[string]any {
+	meta, _ := pc.Get(_frontmatterKey).(map[string]any)
+	return meta
+}
+
+func TestSyntax(t *testing.T) {
+	t.Parallel()
+
+	const (
+		defaultHTML  = `<p><span class="hashtag">#foo</span> #1a</p>`
+		obsidianHTML = `<p><span class="hashtag">#foo</span> <span class="hashtag">#1a</span></p>`
+		disabledHTML = `<p>#foo #1a</p>`
+	)
+
+	tests := []struct {
+		desc        string
+		variant     Variant
+		frontmatter map[string]any
+		syntax      *Syntax
+		directive   string
+		want        string
+	}{
+		{desc: "default", want: defaultHTML},
+		{
+			desc:        "frontmatter/obsidian",
+			frontmatter: map[string]any{"hashtag": "obsidian"},
+			want:        obsidianHTML,
+		},
+		{
+			desc:        "frontmatter/default",
+			variant:     ObsidianVariant,
+			frontmatter: map[string]any{"hashtag": "Default"},
+			want:        defaultHTML,
+		},
+		{
+			desc:        "frontmatter/off",
+			frontmatter: map[string]any{"hashtag": "off"},
+			want:        disabledHTML,
+		},
+		{
+			desc:        "frontmatter/false",
+			frontmatter: map[string]any{"hashtag": false},
+			want:        disabledHTML,
+		},
+		{
+			desc:        "frontmatter/true",
+			variant:     ObsidianVariant,
+			frontmatter: map[string]any{"hashtag": true},
+			want:        obsidianHTML,
+		},
+		{
+			desc:        "frontmatter/unknown",
+			frontmatter: map[string]any{"hashtag": "twitter"},
+			want:        defaultHTML,
+		},
+		{
+			desc:      "directive/obsidian",
+			directive: "<!-- hashtag: obsidian -->",
+			want:      obsidianHTML,
+		},
+		{
+			desc:      "directive/off",
+			directive: "<!--hashtag:off-->",
+			want:      disabledHTML,
+		},
+		{
+			desc:      "directive/unknown",
+			directive: "<!-- hashtag: twitter -->",
+			want:      defaultHTML,
+		},
+		{
+			desc:      "directive/other comment",
+			directive: "<!-- obsidian -->",
+			want:      defaultHTML,
+		},
+		{
+			desc:        "frontmatter over directive",
+			frontmatter: map[string]any{"hashtag": "obsidian"},
+			directive:   "<!-- hashtag: default -->",
+			want:        obsidianHTML,
+		},
+		{
+			desc:      "context",
+			syntax:    &Syntax{Variant: ObsidianVariant},
+			directive: "<!-- hashtag: default -->",
+			want:      obsidianHTML,
+		},
+		{
+			desc:   "context/disabled",
+			syntax: &Syntax{Disabled: true},
+			want:   disabledHTML,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(goldmark.WithExtensions(&Extender{
+				Variant:     tt.variant,
+				Frontmatter: contextFrontmatter,
+			}))
+
+			pc := parser.NewContext()
+			if tt.frontmatter != nil {
+				pc.Set(_frontmatterKey, tt.frontmatter)
+			}
+			if tt.syntax != nil {
+				SetSyntax(pc, *tt.syntax)
+			}
+
+			src := "#foo #1a\n"
+			want := tt.want
+			if tt.directive != "" {
+				src = tt.directive + "\n\n" + src
+				want = "<!-- raw HTML omitted -->\n" + want
+			}
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(src), &buff, parser.WithContext(pc)))
+			assert.Equal(t, want, strings.TrimSpace(buff.String()))
+		})
+	}
+}
+
+func TestSyntax_ReusedContext(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{}))
+	pc := parser.NewContext()
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("<!-- hashtag: off -->\n\n#foo"), &buff, parser.WithContext(pc)))
+	assert.Contains(t, buff.String(), "<p>#foo</p>")
+
+	buff.Reset()
+	require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+	assert.Contains(t, buff.String(), `<span class="hashtag">#foo</span>`)
+}
+
+func TestSyntax_Regions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{
+			desc: "off and on",
+			give: "#a\n\n<!-- hashtag: off -->\n\n#b\n\n<!-- hashtag: on -->\n\n#c\n",
+			want: `<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!-- hashtag: off -->\n" +
+				"<p>#b</p>\n" +
+				"<!-- hashtag: on -->\n" +
+				`<p><span class="hashtag">#c</span></p>` + "\n",
+		},
+		{
+			desc: "off until end",
+			give: "#a\n\n<!--hashtag:OFF-->\n\n#b\n\n#c\n",
+			want: `<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!--hashtag:OFF-->\n" +
+				"<p>#b</p>\n" +
+				"<p>#c</p>\n",
+		},
+		{
+			desc: "off after hashtags",
+			give: "#a\n\n<!-- hashtag: off -->\n\n#b\n",
+			want: `<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!-- hashtag: off -->\n" +
+				"<p>#b</p>\n",
+		},
+		{
+			desc: "off in list",
+			give: "- #a\n\n  <!-- hashtag: off -->\n- #b\n\n#c\n",
+			want: "<ul>\n<li>\n<p><span class=\"hashtag\">#a</span></p>\n" +
+				"<!-- hashtag: off -->\n</li>\n" +
+				"<li>\n<p>#b</p>\n</li>\n</ul>\n" +
+				"<p>#c</p>\n",
+		},
+		{
+			desc: "interrupts paragraph",
+			give: "#a\n<!-- hashtag: off -->\n#b\n",
+			want: `<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!-- hashtag: off -->\n" +
+				"<p>#b</p>\n",
+		},
+		{
+			desc: "nested",
+			give: "> #a\n>\n> <!-- hashtag: off -->\n>\n> #b\n\n#c\n",
+			want: "<blockquote>\n" +
+				`<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!-- hashtag: off -->\n" +
+				"<p>#b</p>\n" +
+				"</blockquote>\n" +
+				"<p>#c</p>\n",
+		},
+		{
+			desc: "skip",
+			give: "<!-- hashtag: skip -->\n- Fixed #a.\n- Fixed #b.\n\n#c\n",
+			want: "<!-- hashtag: skip -->\n" +
+				"<ul>\n<li>Fixed #a.</li>\n<li>Fixed #b.</li>\n</ul>\n" +
+				`<p><span class="hashtag">#c</span></p>` + "\n",
+		},
+		{
+			desc: "skip at end",
+			give: "#a\n\n<!-- hashtag: skip -->\n",
+			want: `<p><span class="hashtag">#a</span></p>` + "\n" +
+				"<!-- hashtag: skip -->\n",
+		},
+		{
+			desc: "not a directive",
+			give: "<!-- hashtag off -->\n\n#a\n",
+			want: "<!-- hashtag off -->\n" +
+				`<p><span class="hashtag">#a</span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{}),
+				goldmark.WithRendererOptions(html.WithUnsafe()),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestSyntax_RegionsWithVariant(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{}))
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte(
+		"<!-- hashtag: obsidian -->\n\n#1a\n\n<!-- hashtag: off -->\n\n#1b\n",
+	), &buff))
+	assert.Equal(t, "<!-- raw HTML omitted -->\n"+
+		`<p><span class="hashtag">#1a</span></p>`+"\n"+
+		"<!-- raw HTML omitted -->\n"+
+		"<p>#1b</p>\n", buff.String())
+}
@@ -0,0 +1,56 @@
+package hashtag
+
+import (
+	"github.com/yuin/goldmark/parser"
+)
+
+// Tag is a distinct hashtag collected from a document.
+type Tag struct {
+	// Name is the canonical form of the hashtag.
+	// See Canonical.
+	Name string
+
+	// Destination is the destination returned by the Resolver
+	// for the hashtag, after sanitization.
+	//
+	// This is empty if the hashtag was not linked.
+	Destination []byte
+
+	// Source reports where in the document the tag was found.
+	Source TagSource
+}
+
+// TagSource specifies where in a document a Tag was found.
+//
+// Sources may be combined with "|"
+// for tags that appear in more than one place.
+type TagSource uint
+
+const (
+	// InlineTag marks tags found as hashtags in the document body.
+	InlineTag TagSource = 1 << iota
+
+	// FrontmatterTag marks tags listed in the document's metadata.
+	FrontmatterTag
+)
+
+// Tags returns the distinct hashtags rendered by the Renderer
+// in the document parsed with pc, in the order they first appear.
+// Hashtags that differ only in case are reported once.
+//
+// Tags are collected only if Renderer.CollectTags is set.
+// They are collected in the same pass that renders the HTML,
+// so this must be called after the document is converted.
+//
+//	pc := parser.NewContext()
+//	if err := md.Convert(src, &buf, parser.WithContext(pc)); err != nil {
+//		// ...
+//	}
+//	tags := hashtag.Tags(pc)
+func Tags(pc parser.Context) []Tag {
+	r := getReport(pc)
+	if r == nil {
+		return nil
+	}
+	return r.collectedTags()
+}
@@ -0,0 +1,152 @@
+package hashtag
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		CollectTags: true,
+		Resolver:    prefixResolver{},
+	}))
+
+	pc := parser.NewContext()
+	pc.Set(_prefixKey, "https://example.com")
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert(
+		[]byte("#Foo and #bar, then #foo again."),
+		&buff, parser.WithContext(pc),
+	))
+
+	assert.Equal(t, []Tag{
+		{Name: "foo", Destination: []byte("https://example.com/tags/Foo"), Source: InlineTag},
+		{Name: "bar", Destination: []byte("https://example.com/tags/bar"), Source: InlineTag},
+	}, Tags(pc))
+}
+
+func TestTags_Unlinked(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		CollectTags:    true,
+		Resolver:       constResolver{Dest: "javascript:alert(1)"},
+		OnResolveError: PlainTextOnResolveError,
+	}))
+
+	pc := parser.NewContext()
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+
+	assert.Equal(t, []Tag{{Name: "foo", Source: InlineTag}}, Tags(pc))
+}
+
+func TestTags_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, Tags(parser.NewContext()))
+	assert.Empty(t, ActivityStreamsTags(nil))
+}
+
+func TestActivityStreamsTags(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		CollectTags: true,
+		Resolver:    prefixResolver{},
+	}))
+
+	pc := parser.NewContext()
+	pc.Set(_prefixKey, "https://example.com")
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert(
+		[]byte("#Foo and #bar, then #foo again."),
+		&buff, parser.WithContext(pc),
+	))
+
+	assert.Equal(t, []ActivityStreamsHashtag{
+		{Type: "Hashtag", Href: "https://example.com/tags/Foo", Name: "#foo"},
+		{Type: "Hashtag", Href: "https://example.com/tags/bar", Name: "#bar"},
+	}, ActivityStreamsTags(Tags(pc)))
+}
+
+func TestActivityStreamsTags_Frontmatter(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		CollectTags: true,
+		Resolver:    prefixResolver{},
+	}))
+
+	pc := parser.NewContext()
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+
+	tags := MergeTags(
+		FrontmatterTags(map[string]any{"tags": "Foo, bar"}),
+		Tags(pc),
+	)
+	assert.Equal(t, []ActivityStreamsHashtag{
+		{Type: "Hashtag", Href: "/tags/foo", Name: "#foo"},
+		{Type: "Hashtag", Name: "#bar"},
+	}, ActivityStreamsTags(tags))
+}
+
+func TestTags_NotCollected(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{}))
+
+	pc := parser.NewContext()
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#foo"), &buff, parser.WithContext(pc)))
+
+	assert.Empty(t, Tags(pc))
+}
+
+// Rendering a parsed document from multiple goroutines
+// must not race on the document's parser.Context.
+// Run with -race.
+func TestTags_ParallelRender(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		Resolver:       constResolver{Err: errors.New("great sadness")},
+		OnResolveError: ErrorClassOnResolveError,
+		CollectTags:    true,
+	}))
+
+	src := []byte("#foo and #bar, then #Foo again.")
+	pc := parser.NewContext()
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var buff bytes.Buffer
+			assert.NoError(t, md.Renderer().Render(&buff, src, doc))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, []Tag{
+		{Name: "foo", Source: InlineTag},
+		{Name: "bar", Source: InlineTag},
+	}, Tags(pc))
+	assert.Len(t, ResolveErrors(pc), 3)
+}
@@ -0,0 +1,120 @@
+package hashtag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// TemplateData is the data passed to the Renderer's Template
+// to render a hashtag.
+//
+// For example, the following template renders a hashtag
+// with an icon and a tooltip.
+//
+//	{{if .Destination -}}
+//	<a class="tag" href="{{.Destination}}" title="{{.Canonical}}">
+//	{{- else -}}
+//	<span class="tag">
+//	{{- end -}}
+//	<i class="icon-tag"></i>{{.Display}}
+//	{{- if .Destination}}</a>{{else}}</span>{{end}}
+type TemplateData struct {
+	// Node is the hashtag node being rendered.
+	Node *Node
+
+	// Text is the hashtag as it appears in the document,
+	// including the leading "#".
+	// For nodes that were not parsed from the document,
+	// this is "#" followed by the tag.
+	Text string
+
+	// Display is the text to display for the hashtag.
+	//
+	// This is the result of the Renderer's Display function if it's set,
+	// and the same as Text otherwise.
+	Display string
+
+	// Tag is the portion of the hashtag following the "#".
+	Tag string
+
+	// Canonical is the canonical form of the hashtag.
+	// See Canonical for details.
+	Canonical string
+
+	// Segments are the levels of a hierarchical hashtag.
+	// For example, the segments of "#a/b/c" are "a", "b", and "c".
+	Segments []string
+
+	// Destination is the link that the hashtag should point to,
+	// or empty if the hashtag should not link to anything.
+	Destination string
+
+	// Missing reports that the Resolver reported the hashtag as missing.
+	Missing bool
+
+	// Attributes are the attributes for the hashtag's link.
+	// These include the Renderer's Attributes,
+	// and those reported by a DetailedResolver.
+	Attributes []Attribute
+
+	// Color is the colour of the hashtag per the Renderer's Style,
+	// or empty if it doesn't have one.
+	Color string
+
+	// Icon is the icon of the hashtag per the Renderer's Style,
+	// or empty if it doesn't have one.
+	Icon string
+
+	// Classes are the per-hashtag classes.
+	// These include those reported by a DetailedResolver,
+	// and the Renderer's MissingClass or ErrorClass if applicable.
+	Classes []string
+}
+
+func (r *Renderer) renderTemplate(w util.BufWriter, src []byte, n *Node) error {
+	if err := ValidateAttributes(r.Attributes); err != nil {
+		return fmt.Errorf("invalid Attributes: %w", err)
+	}
+
+	res, classes, err := r.resolve(src, n)
+	if err != nil {
+		return err
+	}
+
+	attrs := mergeAttributes(expandAttributes(r.Attributes, n), expandAttributes(res.Attributes, n))
+	tag := string(n.Tag)
+	text := string(sourceText(n, src))
+	display := text
+	if r.Display != nil {
+		display = r.Display(n)
+	}
+
+	return r.Template.Execute(w, &TemplateData{
+		Node:        n,
+		Text:        text,
+		Display:     display,
+		Tag:         tag,
+		Canonical:   Canonical(tag),
+		Segments:    strings.Split(tag, "/"),
+		Destination: string(res.Destination),
+		Missing:     res.Missing,
+		Color:       r.Style.Color(tag),
+		Icon:        r.Style.Icon(tag),
+		Attributes:  attrs,
+		Classes:     classes,
+	})
+}
+
+// nodeText returns the text of the hashtag as it appears in the document.
+func nodeText(n *Node, src []byte) []byte {
+	var text []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok {
+			text = append(text, t.Value(src)...)
+		}
+	}
+	return text
+}
@@ -0,0 +1,144 @@
+package hashtag
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer_Template(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("hashtag").Parse(
+		`{{if .Destination}}<a href="{{.Destination}}"` +
+			`{{range .Attributes}}{{if eq .Name "title"}} title="{{.Value}}"{{end}}{{end}}>` +
+			`{{else}}<span class="{{range .Classes}}{{.}} {{end}}tag">{{end}}` +
+			`{{.Text}} ({{.Canonical}}{{range .Segments}}|{{.}}{{end}})` +
+			`{{if .Destination}}</a>{{else}}</span>{{end}}`,
+	))
+
+	tests := []struct {
+		desc     string
+		resolver Resolver
+		give     string
+		want     string
+	}{
+		{
+			desc: "no destination",
+			give: "#Foo/Bar",
+			want: `<p><span class="tag">#Foo/Bar (foo/bar|Foo|Bar)</span></p>` + "\n",
+		},
+		{
+			desc:     "destination",
+			resolver: constResolver{Dest: "/foo"},
+			give:     "#foo",
+			want:     `<p><a href="/foo">#foo (foo|foo)</a></p>` + "\n",
+		},
+		{
+			desc: "attributes and classes",
+			resolver: detailedResolver{
+				Resolution: Resolution{
+					Destination: []byte("/foo"),
+					Attributes:  []Attribute{{Name: "title", Value: `<Foo>`}},
+				},
+			},
+			give: "#foo",
+			want: `<p><a href="/foo" title="&lt;Foo&gt;">#foo (foo|foo)</a></p>` + "\n",
+		},
+		{
+			desc: "missing",
+			resolver: detailedResolver{
+				Resolution: Resolution{Missing: true},
+			},
+			give: "#foo",
+			want: `<p><span class="hashtag-missing tag">#foo (foo|foo)</span></p>` + "\n",
+		},
+		{
+			desc:     "unsafe destination",
+			resolver: constResolver{Dest: "javascript:alert(1)"},
+			give:     "#foo",
+			want:     `<p><span class="tag">#foo (foo|foo)</span></p>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(goldmark.WithExtensions(&Extender{
+				Resolver: tt.resolver,
+				Template: tmpl,
+			}))
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestRenderer_TemplateDisplay(t *testing.T) {
+	t.Parallel()
+
+	md := goldmark.New(goldmark.WithExtensions(&Extender{
+		Display:  func(n *Node) string { return strings.ToUpper(string(n.Tag)) },
+		Template: template.Must(template.New("").Parse(`{{.Display}} ({{.Text}})`)),
+	}))
+
+	var buff bytes.Buffer
+	require.NoError(t, md.Convert([]byte("#foo"), &buff))
+	assert.Equal(t, "<p>FOO (#foo)</p>\n", buff.String())
+}
+
+func TestRenderer_TemplateNoSource(t *testing.T) {
+	t.Parallel()
+
+	r := renderer.NewRenderer(
+		renderer.WithNodeRenderers(
+			util.Prioritized(&Renderer{
+				Template: template.Must(template.New("").Parse(`[{{.Text}}|{{.Display}}]`)),
+			}, 999),
+		),
+	)
+
+	var buff bytes.Buffer
+	require.NoError(t, r.Render(&buff, nil, &Node{Tag: []byte("foo")}))
+	assert.Equal(t, "[#foo|#foo]", buff.String())
+}
+
+func TestRenderer_TemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolve", func(t *testing.T) {
+		t.Parallel()
+
+		giveErr := errors.New("great sadness")
+		md := goldmark.New(goldmark.WithExtensions(&Extender{
+			Resolver: constResolver{Err: giveErr},
+			Template: template.Must(template.New("").Parse(`{{.Tag}}`)),
+		}))
+
+		err := md.Convert([]byte("#foo"), new(bytes.Buffer))
+		assert.ErrorIs(t, err, giveErr)
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		t.Parallel()
+
+		md := goldmark.New(goldmark.WithExtensions(&Extender{
+			Template: template.Must(template.New("").Parse(`{{.Nope}}`)),
+		}))
+
+		err := md.Convert([]byte("#foo"), new(bytes.Buffer))
+		assert.ErrorContains(t, err, "Nope")
+	})
+}
@@ -0,0 +1,218 @@
+package hashtag
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// TextRenderer renders hashtag nodes as plain text.
+//
+//	#foo
+//
+// Renders as the following by default.
+//
+//	#foo
+//
+// Pair it with PlainTextRenderer to render entire documents as plain text
+// (e.g. for search indexes or previews).
+//
+//	renderer.NewRenderer(
+//		renderer.WithNodeRenderers(
+//			util.Prioritized(&hashtag.PlainTextRenderer{}, 1000),
+//			util.Prioritized(&hashtag.TextRenderer{}, 999),
+//		),
+//	)
+type TextRenderer struct {
+	// OmitHash specifies that hashtags should be rendered
+	// without the leading "#".
+	OmitHash bool
+
+	// Prefix and Suffix are written before and after each hashtag
+	// to mark them in the text.
+	//
+	// Defaults to no markers.
+	Prefix, Suffix string
+}
+
+var _ renderer.NodeRenderer = (*TextRenderer)(nil)
+
+// RegisterFuncs registers rendering functions from this renderer onto the
+// provided registerer.
+func (r *TextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(Kind, r.Render)
+}
+
+// Render renders a hashtag node as plain text.
+func (r *TextRenderer) Render(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n, ok := node.(*Node)
+	if !ok {
+		return ast.WalkStop, fmt.Errorf("unexpected node %T, expected *Node", node)
+	}
+
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString(r.Prefix)
+	if !r.OmitHash {
+		_ = w.WriteByte(_hash)
+	}
+	_, _ = w.Write(n.Tag)
+	_, _ = w.WriteString(r.Suffix)
+	return ast.WalkSkipChildren, nil
+}
+
+// PlainTextRenderer renders the core Markdown nodes as plain text.
+//
+// Blocks are separated by blank lines,
+// and inline formatting, links, and raw HTML are dropped,
+// keeping only their text.
+// Use it with TextRenderer to render documents with hashtags.
+//
+// PlainTextRenderer is intentionally minimal.
+// Nodes added by other extensions render only their children.
+type PlainTextRenderer struct{}
+
+var _ renderer.NodeRenderer = (*PlainTextRenderer)(nil)
+
+// RegisterFuncs registers rendering functions from this renderer onto the
+// provided registerer.
+func (r *PlainTextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// Blocks
+	reg.Register(ast.KindParagraph, r.renderBlock)
+	reg.Register(ast.KindHeading, r.renderBlock)
+	reg.Register(ast.KindTextBlock, r.renderBlock)
+	reg.Register(ast.KindBlockquote, r.renderContainer)
+	reg.Register(ast.KindList, r.renderContainer)
+	reg.Register(ast.KindListItem, r.renderContainer)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderSkip)
+	reg.Register(ast.KindThematicBreak, r.renderSkip)
+
+	// Inlines
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindRawHTML, r.renderSkip)
+}
+
+// renderBlock renders blocks that hold inline text.
+func (r *PlainTextRenderer) renderBlock(w util.BufWriter, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		separateBlock(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderContainer renders blocks that hold other blocks.
+func (r *PlainTextRenderer) renderContainer(w util.BufWriter, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		separateBlock(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *PlainTextRenderer) renderCodeBlock(w util.BufWriter, src []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	separateBlock(w, n)
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		_, _ = w.Write(line.Value(src))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *PlainTextRenderer) renderSkip(util.BufWriter, []byte, ast.Node, bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *PlainTextRenderer) renderText(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.Text)
+	value := n.Segment.Value(src)
+	if !n.IsRaw() {
+		value = unescapeText(value)
+	}
+	_, _ = w.Write(value)
+	if n.SoftLineBreak() || n.HardLineBreak() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *PlainTextRenderer) renderString(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.String)
+	value := n.Value
+	if !n.IsRaw() && !n.IsCode() {
+		value = unescapeText(value)
+	}
+	_, _ = w.Write(value)
+	return ast.WalkContinue, nil
+}
+
+func (r *PlainTextRenderer) renderAutoLink(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(node.(*ast.AutoLink).Label(src))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// separateBlock writes a blank line before the given block
+// if it follows another block with output.
+//
+// Items of tight lists are not separated.
+func separateBlock(w util.BufWriter, n ast.Node) {
+	prev := n.PreviousSibling()
+	for prev != nil && (prev.Kind() == ast.KindHTMLBlock || prev.Kind() == ast.KindThematicBreak) {
+		prev = prev.PreviousSibling()
+	}
+	if prev == nil || inTightList(n) {
+		return
+	}
+	_ = w.WriteByte('\n')
+}
+
+// inTightList reports whether the given block is inside a tight list,
+// not counting lists nested inside blockquotes.
+func inTightList(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		switch p := p.(type) {
+		case *ast.List:
+			return p.IsTight
+		case *ast.Blockquote, *ast.Document:
+			return false
+		}
+	}
+	return false
+}
+
+// unescapeText resolves backslash escapes and character references
+// in Markdown text.
+func unescapeText(value []byte) []byte {
+	if bytes.IndexByte(value, '\\') >= 0 {
+		value = util.UnescapePunctuations(value)
+	}
+	if bytes.IndexByte(value, '&') >= 0 {
+		value = util.ResolveNumericReferences(value)
+		value = util.ResolveEntityNames(value)
+	}
+	return value
+}
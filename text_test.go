@@ -0,0 +1,144 @@
+package hashtag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestTextRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		renderer TextRenderer
+		want     string
+	}{
+		{
+			desc: "default",
+			want: "Foo #bar and #baz/qux.\n",
+		},
+		{
+			desc:     "omit hash",
+			renderer: TextRenderer{OmitHash: true},
+			want:     "Foo bar and baz/qux.\n",
+		},
+		{
+			desc:     "marker",
+			renderer: TextRenderer{OmitHash: true, Prefix: "[tag:", Suffix: "]"},
+			want:     "Foo [tag:bar] and [tag:baz/qux].\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{}),
+				goldmark.WithRenderer(renderer.NewRenderer(
+					renderer.WithNodeRenderers(
+						util.Prioritized(&PlainTextRenderer{}, 1000),
+						util.Prioritized(&tt.renderer, 999),
+					),
+				)),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte("Foo #bar and *#baz/qux*."), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}
+
+func TestTextRenderer_WrongNode(t *testing.T) {
+	t.Parallel()
+
+	var r TextRenderer
+	_, err := r.Render(nil, nil, ast.NewText(), true)
+	assert.ErrorContains(t, err, "unexpected node *ast.Text")
+}
+
+func TestPlainTextRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc string
+		give string
+		want string
+	}{
+		{
+			desc: "paragraphs",
+			give: "Foo #bar\nbaz.\n\nQux  \nquux.",
+			want: "Foo #bar\nbaz.\n\nQux\nquux.\n",
+		},
+		{
+			desc: "inline formatting",
+			give: "**Bold** *#em* `code` [link #tag](/foo) ![alt](/img.png) <https://example.com> <b>raw</b>",
+			want: "Bold #em code link #tag alt https://example.com raw\n",
+		},
+		{
+			desc: "escapes",
+			give: `\*not emphasis\* &amp; &copy;`,
+			want: "*not emphasis* & ©\n",
+		},
+		{
+			desc: "heading",
+			give: "# Title #tag\n\nBody.",
+			want: "Title #tag\n\nBody.\n",
+		},
+		{
+			desc: "tight list",
+			give: "- a #b\n- c\n  - d\n\nAfter.",
+			want: "a #b\nc\nd\n\nAfter.\n",
+		},
+		{
+			desc: "loose list",
+			give: "- a\n\n- b",
+			want: "a\n\nb\n",
+		},
+		{
+			desc: "blockquote",
+			give: "> quoted #tag\n>\n> more\n\nAfter.",
+			want: "quoted #tag\n\nmore\n\nAfter.\n",
+		},
+		{
+			desc: "code blocks",
+			give: "Before.\n\n```go\nfoo #notatag\n```\n\n    indented\n\nAfter.",
+			want: "Before.\n\nfoo #notatag\n\nindented\n\nAfter.\n",
+		},
+		{
+			desc: "skipped blocks",
+			give: "Before.\n\n---\n\n<div>html</div>\n\nAfter.",
+			want: "Before.\n\nAfter.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			md := goldmark.New(
+				goldmark.WithExtensions(&Extender{}),
+				goldmark.WithRenderer(renderer.NewRenderer(
+					renderer.WithNodeRenderers(
+						util.Prioritized(&PlainTextRenderer{}, 1000),
+						util.Prioritized(&TextRenderer{}, 999),
+					),
+				)),
+			)
+
+			var buff bytes.Buffer
+			require.NoError(t, md.Convert([]byte(tt.give), &buff))
+			assert.Equal(t, tt.want, buff.String())
+		})
+	}
+}